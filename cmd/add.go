@@ -2,10 +2,17 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
+	"github.com/ChmaraX/notidb/internal/cache"
+	"github.com/ChmaraX/notidb/internal/drafts"
+	"github.com/ChmaraX/notidb/internal/editor"
 	"github.com/ChmaraX/notidb/internal/notion"
+	"github.com/ChmaraX/notidb/internal/placeholders"
 	"github.com/ChmaraX/notidb/internal/settings"
 	"github.com/ChmaraX/notidb/internal/tui"
+	"github.com/ChmaraX/notidb/internal/validation"
 	"github.com/jomei/notionapi"
 	"github.com/spf13/cobra"
 )
@@ -14,20 +21,60 @@ type cmdArgs struct {
 	title   string
 	content string
 	dbId    string
+	// name of the database template
+	template string
+	// name of the template page in Notion
+	notionTemplate string
+	// continue with the unsaved draft of the database
+	resume bool
+	// write the entry in $EDITOR instead of the form
+	edit bool
+	// the entry was filled in the form, its draft is removed once saved
+	interactive bool
+	// the database is bound by the project file
+	projectDb bool
 }
 
 var args cmdArgs
 
 const DefaultTitlePropKey = "title"
-const GreenCheckMark = "\033[32m✓\033[0m"
 
 func (a *cmdArgs) validateDefaultDb() error {
-	if a.dbId == "" {
-		dbId, err := settings.GetDefaultDatabase()
+	if a.dbId != "" {
+		dbId, err := resolveDatabaseRef(a.dbId)
 		if err != nil {
 			return err
 		}
 		a.dbId = dbId
+		return nil
+	}
+
+	projectDbRef, err := settings.GetProjectDatabase()
+	if err != nil {
+		return err
+	}
+	if projectDbRef != "" {
+		dbId, err := resolveDatabaseRef(projectDbRef)
+		if err != nil {
+			return fmt.Errorf("database of the project file: %w", err)
+		}
+		a.dbId = dbId
+		a.projectDb = true
+		return nil
+	}
+
+	dbId, err := settings.GetDefaultDatabase()
+	if err != nil {
+		return err
+	}
+	a.dbId = dbId
+	return nil
+}
+
+// --resume continues the draft in the form, checked before anything is loaded from Notion
+func (a *cmdArgs) validateResume() error {
+	if a.resume && (a.title != "" || a.content != "" || a.edit) {
+		return fmt.Errorf("--resume continues in the form, it can't be used with a title, content or --edit")
 	}
 	return nil
 }
@@ -43,7 +90,7 @@ func createEntryFromArgs(a cmdArgs) notion.DatabaseEntry {
 	}
 
 	if a.content != "" {
-		entry.Blocks = append(entry.Blocks, notion.CreateContentBlock(a.content))
+		entry.Blocks = append(entry.Blocks, notion.CreateParagraphBlock(a.content))
 	}
 
 	return entry
@@ -54,6 +101,10 @@ func saveEntry(dbId string, entry notion.DatabaseEntry) tui.Response {
 	id := "save"
 
 	if err != nil {
+		// the entry may be rejected because the cached schema is outdated, it's loaded again next time
+		if notion.IsValidationError(err) {
+			_ = cache.RemoveDatabase(dbId)
+		}
 		return tui.Response{Id: id, Data: nil, Err: fmt.Errorf("error saving entry: %v", err)}
 	}
 
@@ -66,15 +117,275 @@ func wrappedSaveEntry(dbId string, entry notion.DatabaseEntry) func() tui.Respon
 	}
 }
 
-func createEntry() notion.DatabaseEntry {
-	if args.title == "" && args.content == "" {
-		schema, err := notion.GetDatabaseSchema(args.dbId)
+// adds values from the config for props not given on the command line
+func applyValues(entry *notion.DatabaseEntry, schema notionapi.PropertyConfigs, values map[string]string) error {
+	for name, value := range values {
+		propConfig, ok := schema[name]
+		if !ok {
+			return fmt.Errorf("value set for property %q which is not in the database", name)
+		}
+
+		propType := notionapi.PropertyType(propConfig.GetType())
+		if _, ok := entry.Props[name]; ok {
+			continue
+		}
+		if _, ok := entry.Props[DefaultTitlePropKey]; ok && propType == notionapi.PropertyTypeTitle {
+			continue
+		}
+
+		property, err := notion.CreateProperty(propType, value)
 		if err != nil {
-			fmt.Printf("Error getting DB schema: %v\n", err)
+			return fmt.Errorf("value of property %q: %v", name, err)
 		}
-		return tui.InitForm(schema)
+		entry.Props[name] = property
 	}
-	return createEntryFromArgs(args)
+	return nil
+}
+
+// returns the database defaults overridden by the selected template, with placeholders expanded
+func loadFormValues(dbConfig settings.DatabaseConfig) (tui.FormValues, error) {
+	values := tui.FormValues{Props: make(map[string]string)}
+	for name, value := range dbConfig.Defaults {
+		values.Props[name] = value
+	}
+
+	if args.template != "" {
+		template, ok := dbConfig.Templates[args.template]
+		if !ok || template == nil {
+			return tui.FormValues{}, fmt.Errorf("template %q does not exist, see `notidb template list`", args.template)
+		}
+		for name, value := range template.Values {
+			values.Props[name] = value
+		}
+		values.Body = template.Body
+	}
+
+	// a placeholder that can't be resolved, e.g. {{git.branch}} outside of a repository,
+	// doesn't stop the entry from being added, it's kept as it is
+	expander := placeholders.NewExpander()
+	props, err := expander.ExpandAll(values.Props)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v, the placeholder is kept as it is\n", err)
+	}
+	body, err := expander.Expand(values.Body)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: body: %v, the placeholder is kept as it is\n", err)
+	}
+
+	return tui.FormValues{Props: props, Body: body}, nil
+}
+
+// checks the title and the values from the config against the formats and the form rules,
+// the same ones the form enforces
+func validateValues(schema notionapi.PropertyConfigs, values map[string]string, layout settings.FormConfig) error {
+	for name, propConfig := range schema {
+		propType := notionapi.PropertyType(propConfig.GetType())
+
+		value := values[name]
+		if propType == notionapi.PropertyTypeTitle && args.title != "" {
+			value = args.title
+		}
+
+		if err := validation.Validate(propType, value, layout.Fields[name]); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// finds the template page by the name configured in notion_templates or by its title
+// among the pages with the template checkbox checked, and copies it as an entry
+func loadNotionTemplate(dbConfig settings.DatabaseConfig) tui.Response {
+	id := "notionTemplate"
+	name := args.notionTemplate
+
+	templateProp := dbConfig.TemplateProperty
+	if templateProp == "" {
+		templateProp = notion.DefaultTemplateProperty
+	}
+
+	var page notionapi.Page
+	var err error
+	if pageRef, ok := dbConfig.NotionTemplates[name]; ok {
+		pageId, ok := notion.ParseID(pageRef)
+		if !ok {
+			return tui.Response{Id: id, Data: nil, Err: fmt.Errorf("Notion template %q: %q is not a page ID or URL", name, pageRef)}
+		}
+		page, err = notion.GetPage(pageId)
+	} else {
+		var schema notionapi.PropertyConfigs
+		if schema, err = getDatabaseSchema(args.dbId); err == nil {
+			page, err = notion.FindTemplatePage(args.dbId, schema, templateProp, name)
+		}
+	}
+	if err != nil {
+		return tui.Response{Id: id, Data: nil, Err: fmt.Errorf("Notion template %q: %w", name, err)}
+	}
+
+	entry, err := notion.CopyPage(page, templateProp)
+	if err != nil {
+		return tui.Response{Id: id, Data: nil, Err: fmt.Errorf("error copying Notion template %q: %w", name, err)}
+	}
+
+	return tui.Response{Id: id, Data: entry, Err: nil}
+}
+
+// runs the form, restoring the unsaved draft of the database on --resume or when confirmed
+func fillForm(dbConfig settings.DatabaseConfig, values tui.FormValues) (notion.DatabaseEntry, error) {
+	draft, hasDraft, err := drafts.Load(args.dbId)
+	if err != nil {
+		return notion.DatabaseEntry{}, fmt.Errorf("error loading the draft: %v", err)
+	}
+	if args.resume && !hasDraft {
+		return notion.DatabaseEntry{}, fmt.Errorf("there is no unsaved draft for this database")
+	}
+	if hasDraft && (args.resume || confirm(fmt.Sprintf("Restore the unsaved draft from %s?", draft.SavedAt.Format("02/01/2006 15:04")))) {
+		values = tui.FormValues{Props: draft.Props, Body: draft.Body}
+	}
+
+	schema, err := getDatabaseSchema(args.dbId)
+	if err != nil {
+		fmt.Printf("Error getting DB schema: %v\n", err)
+	}
+
+	args.interactive = true
+	return tui.InitForm(schema, tui.FormOptions{
+		Values: values,
+		Layout: dbConfig.Form,
+		OnAutosave: func(values tui.FormValues) error {
+			return drafts.Save(args.dbId, drafts.Draft{Props: values.Props, Body: values.Body})
+		},
+		OnDiscard: func() error {
+			return drafts.Remove(args.dbId)
+		},
+	}), nil
+}
+
+// opens the entry in $EDITOR with the props as front matter, the title and content
+// from the command line are pre-filled and replaced by the edited values
+func editValues(dbConfig settings.DatabaseConfig, values tui.FormValues) (tui.FormValues, error) {
+	schema, err := getDatabaseSchema(args.dbId)
+	if err != nil {
+		return tui.FormValues{}, fmt.Errorf("error getting DB schema: %v", err)
+	}
+
+	body := values.Body
+	if args.content != "" {
+		body = args.content
+	}
+
+	var fields []editor.Field
+	for _, name := range tui.FormFields(schema, dbConfig.Form, values.Props) {
+		value := values.Props[name]
+		if args.title != "" && notionapi.PropertyType(schema[name].GetType()) == notionapi.PropertyTypeTitle {
+			value = args.title
+		}
+		fields = append(fields, editor.Field{Name: name, Value: value})
+	}
+
+	content, err := editor.Marshal(fields, body)
+	if err != nil {
+		return tui.FormValues{}, err
+	}
+	content, err = editor.Edit(content)
+	if err != nil {
+		return tui.FormValues{}, err
+	}
+	props, body, err := editor.Unmarshal(content)
+	if err != nil {
+		return tui.FormValues{}, err
+	}
+
+	// values of hidden props are kept
+	edited := tui.FormValues{Props: make(map[string]string), Body: body}
+	for name, value := range values.Props {
+		edited.Props[name] = value
+	}
+	for name, value := range props {
+		if _, ok := schema[name]; !ok {
+			return tui.FormValues{}, fmt.Errorf("unknown property %q in the front matter", name)
+		}
+		if value == "" {
+			delete(edited.Props, name)
+		} else {
+			edited.Props[name] = value
+		}
+	}
+
+	args.title, args.content = "", ""
+	return edited, nil
+}
+
+func createEntry() (notion.DatabaseEntry, error) {
+	dbConfig, err := settings.GetDatabaseConfig(args.dbId, args.projectDb)
+	if err != nil {
+		return notion.DatabaseEntry{}, err
+	}
+
+	values, err := loadFormValues(dbConfig)
+	if err != nil {
+		return notion.DatabaseEntry{}, err
+	}
+
+	// loaded before the form, so a missing template is reported before anything is typed
+	var notionTemplate *notion.DatabaseEntry
+	if args.notionTemplate != "" {
+		m := tui.NewLoadingModel("Loading Notion template", func() tui.Response {
+			return loadNotionTemplate(dbConfig)
+		})
+		res := m.GetResponse("notionTemplate")
+		if res.Err != nil {
+			return notion.DatabaseEntry{}, res.Err
+		}
+		template := res.Data.(notion.DatabaseEntry)
+		notionTemplate = &template
+	}
+
+	if args.edit {
+		values, err = editValues(dbConfig, values)
+		if err != nil {
+			return notion.DatabaseEntry{}, err
+		}
+		if len(values.Props) == 0 && strings.TrimSpace(values.Body) == "" {
+			return notion.DatabaseEntry{}, nil
+		}
+	}
+
+	var entry notion.DatabaseEntry
+	if !args.edit && args.title == "" && args.content == "" {
+		entry, err = fillForm(dbConfig, values)
+		if err != nil {
+			return notion.DatabaseEntry{}, err
+		}
+		// the form was quit without saving
+		if entry.Props == nil && entry.Blocks == nil {
+			return entry, nil
+		}
+	} else {
+		entry = createEntryFromArgs(args)
+		if args.content == "" && values.Body != "" {
+			entry.Blocks = notion.MarkdownToBlocks(values.Body)
+		}
+
+		// the schema is only needed for the values and rules from the config
+		if len(values.Props) > 0 || len(dbConfig.Form.Fields) > 0 {
+			schema, err := getDatabaseSchema(args.dbId)
+			if err != nil {
+				return notion.DatabaseEntry{}, fmt.Errorf("error getting DB schema: %v", err)
+			}
+			if err := validateValues(schema, values.Props, dbConfig.Form); err != nil {
+				return notion.DatabaseEntry{}, err
+			}
+			if err := applyValues(&entry, schema, values.Props); err != nil {
+				return notion.DatabaseEntry{}, err
+			}
+		}
+	}
+
+	if notionTemplate != nil {
+		entry = notion.MergeEntry(entry, *notionTemplate)
+	}
+	return entry, nil
 }
 
 /**
@@ -99,16 +410,29 @@ var addEntryCmd = &cobra.Command{
 	Aliases: []string{"a"},
 	Short:   "Adds a new entry to the database",
 	Run: func(cmd *cobra.Command, arguments []string) {
-		if err := args.validateDefaultDb(); err != nil {
+		loadShortcutArgs(arguments)
+
+		if err := args.validateResume(); err != nil {
 			fmt.Printf("Error: %v\n", err)
 			return
 		}
 
-		loadShortcutArgs(arguments)
+		if err := args.validateDefaultDb(); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
 
-		entry := createEntry()
+		entry, err := createEntry()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
 
 		if entry.Props == nil && entry.Blocks == nil {
+			if _, hasDraft, _ := drafts.Load(args.dbId); args.interactive && hasDraft {
+				fmt.Println("Draft kept, continue with `notidb add --resume`")
+				return
+			}
 			fmt.Println("No content to save")
 			return
 		}
@@ -121,12 +445,22 @@ var addEntryCmd = &cobra.Command{
 			return
 		}
 
+		if args.interactive {
+			if err := drafts.Remove(args.dbId); err != nil {
+				fmt.Printf("Warning: failed to remove the draft: %v\n", err)
+			}
+		}
+
 		url := res.Data.(string)
-		fmt.Printf("\n %s Saved: %s\n\n", GreenCheckMark, url)
+		fmt.Printf("\n %s Saved: %s\n\n", CheckMark, url)
 	},
 }
 
 func init() {
 	addEntryCmd.Flags().StringVarP(&args.title, "title", "t", "", "Title of the new entry")
 	addEntryCmd.Flags().StringVarP(&args.content, "content", "c", "", "Content of the new entry")
+	addEntryCmd.Flags().StringVar(&args.template, "template", "", "Template of the database to pre-fill the entry with")
+	addEntryCmd.Flags().StringVar(&args.notionTemplate, "notion-template", "", "Title of a page of the database marked as template by the Template checkbox (or template_property), its properties and content are copied")
+	addEntryCmd.Flags().BoolVar(&args.resume, "resume", false, "Continue with the unsaved draft of the database")
+	addEntryCmd.Flags().BoolVarP(&args.edit, "edit", "e", false, "Write the entry in $EDITOR, properties go to the YAML front matter")
 }
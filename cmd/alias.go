@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ChmaraX/notidb/internal/settings"
+	"github.com/ChmaraX/notidb/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var aliasCmd = &cobra.Command{
+	Use:         "alias",
+	Short:       "Manage database aliases usable with the --db flag",
+	Annotations: map[string]string{skipClientAnnotation: "true"},
+}
+
+var aliasSetCmd = &cobra.Command{
+	Use:   "set <alias> [database]",
+	Short: "Sets an alias for a database picked from the list or given by ID, URL or title",
+	Args:  cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, arguments []string) {
+		alias := arguments[0]
+
+		if len(arguments) == 2 {
+			dbId, err := resolveDatabaseRef(arguments[1])
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			if err := settings.SetAlias(alias, dbId); err != nil {
+				fmt.Printf("Error setting alias: %v\n", err)
+				return
+			}
+			fmt.Printf("\n %s Alias %s set to: %s\n\n", CheckMark, alias, dbId)
+			return
+		}
+
+		dbs, defaultDbId, err := loadDatabasesWithDefault()
+		if err != nil {
+			fmt.Printf("\n%s\n", err)
+			return
+		}
+
+		tui.InitDbListModel(dbs.dbs, defaultDbId, dbs.listOptions(tui.DbListOptions{
+			Title:      fmt.Sprintf("Choose database for alias %q:", alias),
+			SuccessMsg: fmt.Sprintf("Alias %s successfully set to", alias),
+			QuitMsg:    "No changes made.",
+			OnSelect: func(dbId string) error {
+				return settings.SetAlias(alias, dbId)
+			},
+		}))
+	},
+}
+
+var aliasListCmd = &cobra.Command{
+	Use:         "list",
+	Aliases:     []string{"ls"},
+	Short:       "Lists all database aliases",
+	Annotations: map[string]string{skipClientAnnotation: "true"},
+	Run: func(cmd *cobra.Command, arguments []string) {
+		aliases, err := settings.GetAliases()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		if len(aliases) == 0 {
+			fmt.Println("No aliases set, add one with `notidb alias set <alias>`")
+			return
+		}
+
+		names := make([]string, 0, len(aliases))
+		for name := range aliases {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			fmt.Printf("%s\t%s\n", name, aliases[name])
+		}
+	},
+}
+
+var aliasRemoveCmd = &cobra.Command{
+	Use:         "remove <alias>",
+	Aliases:     []string{"rm"},
+	Short:       "Removes a database alias",
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{skipClientAnnotation: "true"},
+	Run: func(cmd *cobra.Command, arguments []string) {
+		if err := settings.RemoveAlias(arguments[0]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		fmt.Printf("\n %s Alias removed: %s\n\n", CheckMark, arguments[0])
+	},
+}
+
+func init() {
+	aliasCmd.AddCommand(aliasSetCmd)
+	aliasCmd.AddCommand(aliasListCmd)
+	aliasCmd.AddCommand(aliasRemoveCmd)
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ChmaraX/notidb/internal/cache"
+	"github.com/ChmaraX/notidb/internal/keyring"
+	"github.com/ChmaraX/notidb/internal/notion"
+	"github.com/ChmaraX/notidb/internal/settings"
+	"github.com/ChmaraX/notidb/internal/tui"
+	"github.com/jomei/notionapi"
+	"github.com/spf13/cobra"
+)
+
+type authArgs struct {
+	apiKeyStdin bool
+}
+
+var authFlags authArgs
+
+type authStatus struct {
+	user    notionapi.User
+	dbCount int
+}
+
+func loadAuthStatus() tui.Response {
+	id := "status"
+
+	user, err := notion.GetBotUser()
+	if err != nil {
+		return tui.Response{Id: id, Data: nil, Err: err}
+	}
+
+	dbs, err := notion.GetAllNotionDbs()
+	if err != nil {
+		return tui.Response{Id: id, Data: nil, Err: fmt.Errorf("error loading databases: %v", err)}
+	}
+
+	return tui.Response{Id: id, Data: authStatus{user: user, dbCount: len(dbs)}, Err: nil}
+}
+
+var authCmd = &cobra.Command{
+	Use:         "auth",
+	Short:       "Manage the Notion API key of the current profile",
+	Annotations: map[string]string{skipClientAnnotation: "true"},
+}
+
+var authStatusCmd = &cobra.Command{
+	Use:         "status",
+	Short:       "Shows which integration and workspace the API key belongs to",
+	Annotations: map[string]string{skipClientAnnotation: "true"},
+	Run: func(cmd *cobra.Command, arguments []string) {
+		profile, err := settings.CurrentProfile()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		apiKey, source, err := resolveAPIKey()
+		if err != nil {
+			fmt.Printf("Profile %s is not logged in: %v\n", profile, err)
+			return
+		}
+		notion.CreateNotionClient(apiKey)
+
+		m := tui.NewLoadingModel("Calling Notion API - checking API key", loadAuthStatus)
+		res := m.GetResponse("status")
+		if res.Err != nil {
+			fmt.Printf("\n%s\n", res.Err)
+			return
+		}
+		status := res.Data.(authStatus)
+
+		workspace := "-"
+		if status.user.Bot != nil && status.user.Bot.WorkspaceName != "" {
+			workspace = status.user.Bot.WorkspaceName
+		}
+
+		fmt.Printf("\n %s Logged in\n\n", CheckMark)
+		fmt.Printf("  Profile:     %s\n", profile)
+		fmt.Printf("  Integration: %s\n", status.user.Name)
+		fmt.Printf("  Workspace:   %s\n", workspace)
+		fmt.Printf("  Databases:   %d\n", status.dbCount)
+		fmt.Printf("  Key source:  %s\n\n", source)
+	},
+}
+
+var authLogoutCmd = &cobra.Command{
+	Use:         "logout",
+	Short:       "Removes the API key from the keyring and clears the profile settings",
+	Annotations: map[string]string{skipClientAnnotation: "true"},
+	Run: func(cmd *cobra.Command, arguments []string) {
+		// cleared first, the keyring may not be available when the key is read by the command
+		if err := settings.SetAPIKeyCommand(""); err != nil {
+			fmt.Printf("Error clearing API key command: %v\n", err)
+			return
+		}
+
+		keyring, err := newKeyringManager()
+		if err != nil {
+			fmt.Printf("Error initializing keyring: %v\n", err)
+			return
+		}
+		if err := keyring.RemoveAPIKey(); err != nil {
+			fmt.Printf("Error removing API key: %v\n", err)
+			return
+		}
+
+		if err := settings.ResetProfile(); err != nil {
+			fmt.Printf("Error clearing settings: %v\n", err)
+			return
+		}
+		if err := cache.ClearProfile(); err != nil {
+			fmt.Printf("Error clearing cache: %v\n", err)
+			return
+		}
+
+		fmt.Printf("\n %s Logged out\n\n", CheckMark)
+		warnEnvAPIKey()
+	},
+}
+
+var authRotateCmd = &cobra.Command{
+	Use:         "rotate",
+	Short:       "Replaces the stored API key with a new one",
+	Annotations: map[string]string{skipClientAnnotation: "true"},
+	Run: func(cmd *cobra.Command, arguments []string) {
+		command, err := settings.GetAPIKeyCommand()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		keyring, err := newKeyringManager()
+		if err != nil {
+			fmt.Printf("Error initializing keyring: %v\n", err)
+			return
+		}
+
+		apiKey := validateAPIKey(readAPIKey(authFlags.apiKeyStdin, false))
+
+		if err := keyring.SaveAPIKey(apiKey); err != nil {
+			fmt.Printf("Error saving API key: %v\n", err)
+			return
+		}
+		// the command would take precedence over the new key
+		if err := settings.SetAPIKeyCommand(""); err != nil {
+			fmt.Printf("Error clearing API key command: %v\n", err)
+			return
+		}
+		// the new key can be of another workspace
+		if err := cache.ClearProfile(); err != nil {
+			fmt.Printf("Error clearing cache: %v\n", err)
+			return
+		}
+
+		fmt.Printf("\n %s API key replaced\n\n", CheckMark)
+		if command != "" {
+			fmt.Printf("The key is no longer read by the command `%s`, it's stored in the keyring instead\n", command)
+		}
+		warnEnvAPIKey()
+	},
+}
+
+// env vars take precedence over the keyring, so changes to the keyring have no effect while they are set
+func warnEnvAPIKey() {
+	if _, envVar, ok := keyring.LookupEnvAPIKey(); ok {
+		fmt.Printf("Note: %s is set and is used instead of the keyring\n", envVar)
+	}
+}
+
+func init() {
+	authRotateCmd.Flags().BoolVar(&authFlags.apiKeyStdin, "api-key-stdin", false, "Read the new API key from stdin")
+
+	authCmd.AddCommand(authStatusCmd)
+	authCmd.AddCommand(authLogoutCmd)
+	authCmd.AddCommand(authRotateCmd)
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ChmaraX/notidb/internal/cache"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:         "cache",
+	Short:       "Manage the cache of databases and their schemas",
+	Annotations: map[string]string{skipClientAnnotation: "true"},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:         "clear",
+	Short:       "Removes the cached databases and schemas of all profiles",
+	Annotations: map[string]string{skipClientAnnotation: "true"},
+	Run: func(cmd *cobra.Command, arguments []string) {
+		if err := cache.Clear(); err != nil {
+			fmt.Printf("Error clearing the cache: %v\n", err)
+			return
+		}
+		fmt.Printf("\n %s Cache cleared\n\n", CheckMark)
+	},
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheClearCmd)
+}
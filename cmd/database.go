@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"github.com/ChmaraX/notidb/internal/cache"
+	"github.com/ChmaraX/notidb/internal/notion"
+	"github.com/ChmaraX/notidb/internal/settings"
+	"github.com/jomei/notionapi"
+)
+
+// resolves a database reference given by the user to a database ID,
+// the reference can be an alias, an ID, a Notion URL or an exact database title
+func resolveDatabaseRef(ref string) (string, error) {
+	aliases, err := settings.GetAliases()
+	if err != nil {
+		return "", err
+	}
+	if dbId, ok := aliases[ref]; ok {
+		return dbId, nil
+	}
+
+	if dbId, ok := notion.ParseID(ref); ok {
+		return dbId, nil
+	}
+
+	// a title unique in the cached databases saves the search of the workspace
+	if dbs, ok := cache.GetDatabases(); ok {
+		var matches []string
+		for _, db := range dbs.Dbs {
+			if notion.DatabaseTitle(db) == ref {
+				matches = append(matches, string(db.ID))
+			}
+		}
+		if len(matches) == 1 {
+			return matches[0], nil
+		}
+	}
+
+	db, err := notion.FindDatabaseByTitle(ref)
+	if err != nil {
+		return "", err
+	}
+	return string(db.ID), nil
+}
+
+// returns the database from the cache, it's loaded from Notion and cached when it's missing or expired
+func getDatabase(dbId string) (notionapi.Database, error) {
+	// schemas edited in Notion since the list was cached are replaced first
+	if !cache.DatabasesChecked() {
+		if dbs, err := notion.RecentDatabases(); err == nil {
+			_ = cache.SaveRecentDatabases(dbs)
+		}
+	}
+
+	if db, ok := cache.GetDatabase(dbId); ok {
+		return db, nil
+	}
+
+	db, err := notion.GetDatabase(dbId)
+	if err != nil {
+		return notionapi.Database{}, err
+	}
+	// the cache only saves requests, failing to write it isn't an error
+	_ = cache.SaveDatabase(db)
+	return db, nil
+}
+
+// returns the schema of the database, see getDatabase
+func getDatabaseSchema(dbId string) (notionapi.PropertyConfigs, error) {
+	db, err := getDatabase(dbId)
+	if err != nil {
+		return nil, err
+	}
+	return db.Properties, nil
+}
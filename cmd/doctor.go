@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ChmaraX/notidb/internal/notion"
+	"github.com/ChmaraX/notidb/internal/settings"
+	"github.com/ChmaraX/notidb/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+type doctorReport struct {
+	failed bool
+}
+
+func (r *doctorReport) ok(msg string, a ...interface{}) {
+	fmt.Printf(" %s %s\n", CheckMark, fmt.Sprintf(msg, a...))
+}
+
+func (r *doctorReport) warn(fix, msg string, a ...interface{}) {
+	fmt.Printf(" %s %s\n", WarningMark, fmt.Sprintf(msg, a...))
+	fmt.Printf("   -> %s\n", fix)
+}
+
+func (r *doctorReport) fail(fix, msg string, a ...interface{}) {
+	r.failed = true
+	fmt.Printf(" %s %s\n", CrossMark, fmt.Sprintf(msg, a...))
+	fmt.Printf("   -> %s\n", fix)
+}
+
+var doctorCmd = &cobra.Command{
+	Use:         "doctor",
+	Short:       "Diagnoses the NotiDB setup and suggests fixes",
+	Annotations: map[string]string{skipClientAnnotation: "true"},
+	Run: func(cmd *cobra.Command, arguments []string) {
+		r := &doctorReport{}
+		fmt.Println()
+
+		if checkSettings(r) && checkCredentials(r) && checkAPI(r) {
+			checkDefaultDatabase(r)
+		}
+
+		fmt.Println()
+		if r.failed {
+			os.Exit(1)
+		}
+	},
+}
+
+func checkSettings(r *doctorReport) bool {
+	filePath, err := settings.SettingsFilePath()
+	if err != nil {
+		r.fail("make sure the HOME directory is set", "Config file location: %v", err)
+		return false
+	}
+
+	if info, err := os.Stat(filepath.Dir(filePath)); err == nil && info.Mode().Perm()&0077 != 0 {
+		r.warn(fmt.Sprintf("run `chmod 700 %s`", filepath.Dir(filePath)), "Config directory is accessible by other users (%s)", info.Mode().Perm())
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		r.fail("run `notidb init`", "Config file %s: %v", filePath, err)
+		return false
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		r.warn(fmt.Sprintf("run `chmod 600 %s`", filePath), "Config file is accessible by other users (%s)", info.Mode().Perm())
+	}
+
+	if err := settings.CheckSettingsFile(); err != nil {
+		r.fail(fmt.Sprintf("fix or remove %s and run `notidb init`", filePath), "Config is not valid: %v", err)
+		return false
+	}
+	r.ok("Config file %s", filePath)
+
+	if projectFilePath, err := settings.ProjectFilePath(); err == nil && projectFilePath != "" {
+		r.ok("Project file %s", projectFilePath)
+	}
+
+	profile, err := settings.CurrentProfile()
+	if err != nil {
+		r.fail("check the config file", "Profile: %v", err)
+		return false
+	}
+	if _, err := settings.GetDefaultDatabase(); err != nil {
+		r.fail(fmt.Sprintf("run `notidb profile add %s` or select an existing profile", profile), "Profile %s: %v", profile, err)
+		return false
+	}
+	r.ok("Profile %s", profile)
+
+	return true
+}
+
+func checkCredentials(r *doctorReport) bool {
+	// the keyring is checked even if the key comes from elsewhere, as init and auth commands need it
+	if _, err := newKeyringManager(); err != nil {
+		r.warn("use the file or pass keyring backend, see `notidb init --help`", "Keyring: %v", err)
+	} else {
+		r.ok("Keyring available")
+	}
+
+	apiKey, source, err := resolveAPIKey()
+	if err != nil {
+		r.fail("run `notidb init` or set the NOTIDB_API_KEY env var", "API key: %v", err)
+		return false
+	}
+	r.ok("API key found (%s)", source)
+
+	notion.CreateNotionClient(apiKey)
+	return true
+}
+
+func checkAPI(r *doctorReport) bool {
+	user, err := notion.GetBotUser()
+	if errors.Is(err, notion.ErrInvalidAPIKey) {
+		r.fail("run `notidb auth rotate` with a valid integration secret", "API key is invalid")
+		return false
+	}
+	if err != nil {
+		r.fail("check your internet connection and proxy settings", "Notion API is not reachable: %v", err)
+		return false
+	}
+	r.ok("Notion API reachable, API key belongs to integration %s", user.Name)
+
+	return true
+}
+
+func checkDefaultDatabase(r *doctorReport) {
+	dbId, err := settings.GetDefaultDatabase()
+	if err != nil {
+		r.fail("check the config file", "Default database: %v", err)
+		return
+	}
+	if dbId == settings.NoDefaultDatabaseId {
+		r.warn("run `notidb sd` to set one", "No default database set")
+		return
+	}
+
+	db, err := notion.GetDatabase(dbId)
+	if err != nil {
+		r.fail("share the database with the integration or run `notidb sd` to pick another one", "Default database: %v", err)
+		return
+	}
+	r.ok("Default database %s accessible", notion.DatabaseTitle(db))
+
+	skipped := tui.SkippedProps(db.Properties)
+	if len(skipped) == 0 {
+		r.ok("All properties which can be set are supported by the form")
+		return
+	}
+
+	names := make([]string, 0, len(skipped))
+	for name, propType := range skipped {
+		names = append(names, fmt.Sprintf("%s (%s)", name, propType))
+	}
+	sort.Strings(names)
+
+	r.warn("these properties are left empty when adding entries", "%d of %d properties are not supported by the form: %s", len(skipped), len(db.Properties), strings.Join(names, ", "))
+}
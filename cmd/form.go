@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ChmaraX/notidb/internal/settings"
+	"github.com/ChmaraX/notidb/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var formCmd = &cobra.Command{
+	Use:   "form",
+	Short: "Manage the layout of the add form of the database",
+}
+
+var formConfigureCmd = &cobra.Command{
+	Use:   "configure",
+	Short: "Reorders, hides and marks required the fields of the add form",
+	Run: func(cmd *cobra.Command, arguments []string) {
+		if err := args.validateDefaultDb(); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		schema, err := getDatabaseSchema(args.dbId)
+		if err != nil {
+			fmt.Printf("Error getting DB schema: %v\n", err)
+			return
+		}
+
+		dbConfig, err := settings.GetDatabaseConfig(args.dbId, false)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		layout, ok := tui.InitFormConfig(schema, dbConfig.Form)
+		if !ok {
+			fmt.Println("No changes made.")
+			return
+		}
+
+		if err := settings.SaveFormConfig(args.dbId, layout); err != nil {
+			fmt.Printf("Error saving form layout: %v\n", err)
+			return
+		}
+		fmt.Printf("\n %s Form layout saved\n\n", CheckMark)
+	},
+}
+
+func init() {
+	formCmd.AddCommand(formConfigureCmd)
+}
@@ -2,57 +2,249 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
 
+	"github.com/ChmaraX/notidb/internal/cache"
 	"github.com/ChmaraX/notidb/internal/keyring"
 	"github.com/ChmaraX/notidb/internal/notion"
+	"github.com/ChmaraX/notidb/internal/settings"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
 )
 
+const keyringFileDir = "keyring"
+
+type initArgs struct {
+	keyringBackend string
+	apiKeyCommand  string
+	apiKeyStdin    bool
+	noInput        bool
+}
+
+var initFlags initArgs
+
 var initCmd = &cobra.Command{
-	Use:     "init",
-	Aliases: []string{"i"},
-	Short:   "Initializes NotiDB CLI",
-	Run: func(cmd *cobra.Command, args []string) {
+	Use:         "init",
+	Aliases:     []string{"i"},
+	Short:       "Initializes NotiDB CLI",
+	Annotations: map[string]string{skipClientAnnotation: "true"},
+	Run: func(cmd *cobra.Command, arguments []string) {
+		runInit(cmd, arguments, nil)
+	},
+}
 
-		keyring, err := keyring.NewKeyringManager()
-		if err != nil {
-			log.Fatalf("Error initializing keyring: %s\n", err)
+// initializes the current profile, used by init and `profile add`, the cleanup runs
+// before init exits on an error or an interrupt
+func runInit(cmd *cobra.Command, arguments []string, cleanup func()) {
+	if cleanup != nil {
+		initCleanup = cleanup
+		interrupts := make(chan os.Signal, 1)
+		done := make(chan struct{})
+		signal.Notify(interrupts, os.Interrupt)
+		go func() {
+			select {
+			case <-interrupts:
+				fmt.Println()
+				cleanup()
+				os.Exit(130)
+			case <-done:
+			}
+		}()
+		defer func() {
+			signal.Stop(interrupts)
+			close(done)
+			initCleanup = nil
+		}()
+	}
+
+	if initFlags.keyringBackend != "" {
+		if err := settings.SetKeyringBackend(initFlags.keyringBackend); err != nil {
+			initFatalf("Error saving keyring backend: %s\n", err)
 		}
+	}
 
-		fmt.Print("Please enter your Notion API key: ")
-		apiKeyBytes, err := term.ReadPassword(int(os.Stdin.Fd()))
+	var apiKey string
+	if initFlags.apiKeyCommand != "" {
+		apiKey = initWithAPIKeyCommand(initFlags.apiKeyCommand)
+	} else {
+		if envKey, envVar, ok := keyring.LookupEnvAPIKey(); ok && !initFlags.apiKeyStdin {
+			// the env var takes precedence over the keyring anyway, so there is nothing to store
+			fmt.Printf("Using API key from %s, it won't be stored\n", envVar)
+			apiKey = validateAPIKey(envKey)
+		} else {
+			apiKey = initWithKeyring()
+		}
+		// a command saved by an earlier init would take precedence over the keyring
+		if err := settings.SetAPIKeyCommand(""); err != nil {
+			initFatalf("Error clearing API key command: %s\n", err)
+		}
+	}
+
+	notion.CreateNotionClient(apiKey)
+
+	// the cached databases can be of the workspace of the previous key
+	if err := cache.ClearProfile(); err != nil {
+		initFatalf("Error clearing cache: %s\n", err)
+	}
+
+	switch {
+	case args.dbId != "":
+		db, err := setDefaultDbFromRef(args.dbId)
 		if err != nil {
-			log.Fatalf("\nError reading API key: %s\n", err)
+			initFatalf("Error: %s\n", err)
 		}
-		apiKey := string(apiKeyBytes)
+		fmt.Printf("Default database set to: %s\n", notion.DatabaseTitle(db))
+	case initFlags.noInput:
+		fmt.Println("No default database set, use --db or run `notidb sd` later")
+	default:
+		// prompt for default database
+		setDefaultDbCmd.Run(cmd, arguments)
+	}
+
+	fmt.Printf("\n %s NotiDB CLI initialized\n\n", CheckMark)
+}
+
+// run before init exits on an error, e.g. to remove the profile added for it
+var initCleanup func()
+
+// prints the error and exits like log.Fatalf, the cleanup runs before the exit
+func initFatalf(format string, v ...interface{}) {
+	log.Printf(format, v...)
+	if initCleanup != nil {
+		initCleanup()
+	}
+	os.Exit(1)
+}
+
+func initWithKeyring() string {
+	keyring, err := newKeyringManager()
+	if err != nil {
+		initFatalf("Error initializing keyring: %s\n", err)
+	}
+
+	apiKey := validateAPIKey(readAPIKey(initFlags.apiKeyStdin, initFlags.noInput))
 
-		fmt.Println() // Print a new line after the user's input
+	if err := keyring.SaveAPIKey(apiKey); err != nil {
+		initFatalf("Error saving API key: %s\n", err)
+	}
+
+	return apiKey
+}
 
-		if err := keyring.SaveAPIKey(apiKey); err != nil {
-			log.Fatalf("Error saving API key: %s\n", err)
+func readAPIKey(fromStdin, noInput bool) string {
+	if fromStdin {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			initFatalf("Error reading API key from stdin: %s\n", err)
 		}
+		return strings.TrimSpace(string(data))
+	}
 
-		// prompt for default database
-		notion.CreateNotionClient(apiKey)
-		setDefaultDbCmd.Run(cmd, args)
+	if noInput {
+		initFatalf("Error: no API key provided, use --api-key-stdin, --api-key-command or the %s env var\n", keyring.APIKeyEnvVar)
+	}
 
-		fmt.Printf("\n %s NotiDB CLI initialized\n\n", GreenCheckMark)
-	},
+	fmt.Print("Please enter your Notion API key: ")
+	apiKeyBytes, err := term.ReadPassword(int(os.Stdin.Fd()))
+	if err != nil {
+		initFatalf("\nError reading API key: %s\n", err)
+	}
+
+	fmt.Println() // Print a new line after the user's input
+
+	return string(apiKeyBytes)
 }
 
-func initNotionClient() {
-	keyring, err := keyring.NewKeyringManager()
+// the key is validated only during init, other commands rely on API errors
+func validateAPIKey(apiKey string) string {
+	if err := notion.ValidateNotionAPIKey(apiKey); err != nil {
+		initFatalf("Error validating API key: %s\n", err)
+	}
+	return apiKey
+}
+
+// the key is not stored anywhere, the command is run on every invocation instead
+func initWithAPIKeyCommand(command string) string {
+	apiKey, err := keyring.RunAPIKeyCommand(command)
 	if err != nil {
-		log.Fatalf("Error initializing keyring: %s\n", err)
+		initFatalf("Error reading API key: %s\n", err)
 	}
+	validateAPIKey(apiKey)
 
+	if err := settings.SetAPIKeyCommand(command); err != nil {
+		initFatalf("Error saving API key command: %s\n", err)
+	}
+
+	return apiKey
+}
+
+func newKeyringManager() (*keyring.KeyringManager, error) {
+	profile, err := settings.CurrentProfile()
+	if err != nil {
+		return nil, err
+	}
+	backend, err := settings.GetKeyringBackend()
+	if err != nil {
+		return nil, err
+	}
+	appDir, err := settings.AppDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return keyring.NewKeyringManager(keyring.Options{
+		Profile: profile,
+		Backend: backend,
+		FileDir: filepath.Join(appDir, keyringFileDir),
+	})
+}
+
+// returns the API key together with its source, in order of precedence:
+// NOTIDB_API_KEY or NOTION_TOKEN env vars, API key command, keyring
+func resolveAPIKey() (string, string, error) {
+	if apiKey, envVar, ok := keyring.LookupEnvAPIKey(); ok {
+		return apiKey, "env " + envVar, nil
+	}
+
+	command, err := settings.GetAPIKeyCommand()
+	if err != nil {
+		return "", "", err
+	}
+	if command != "" {
+		apiKey, err := keyring.RunAPIKeyCommand(command)
+		return apiKey, fmt.Sprintf("api key command `%s`", command), err
+	}
+
+	keyring, err := newKeyringManager()
+	if err != nil {
+		return "", "", fmt.Errorf("error initializing keyring: %v", err)
+	}
 	apiKey, err := keyring.GetAPIKey()
+	return apiKey, "keyring", err
+}
+
+func initNotionClient() {
+	apiKey, _, err := resolveAPIKey()
 	if err != nil {
 		log.Fatalf("Error getting API key: %s\nNotiDB CLI might not be initialized. Please run `notidb init` first.\n", err)
 	}
 
 	notion.CreateNotionClient(apiKey)
 }
+
+// registers the init flags, `profile add` takes them too as it initializes the new profile
+func addInitFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&initFlags.keyringBackend, "keyring-backend", "", "Keyring backend to store the API key in: auto, keychain, secret-service, pass or file")
+	cmd.Flags().StringVar(&initFlags.apiKeyCommand, "api-key-command", "", "Command printing the API key, run instead of storing the key")
+	cmd.Flags().BoolVar(&initFlags.apiKeyStdin, "api-key-stdin", false, "Read the API key from stdin")
+	cmd.Flags().BoolVar(&initFlags.noInput, "no-input", false, "Never prompt, fail instead of asking for missing values")
+}
+
+func init() {
+	addInitFlags(initCmd)
+}
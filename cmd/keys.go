@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ChmaraX/notidb/internal/settings"
+	"github.com/ChmaraX/notidb/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var keysCmd = &cobra.Command{
+	Use:         "keys",
+	Short:       "Manage the key bindings of the TUI",
+	Annotations: map[string]string{skipClientAnnotation: "true"},
+}
+
+var keysListCmd = &cobra.Command{
+	Use:         "list",
+	Aliases:     []string{"ls"},
+	Short:       "Lists the key bindings in use",
+	Annotations: map[string]string{skipClientAnnotation: "true"},
+	Run: func(cmd *cobra.Command, arguments []string) {
+		config, err := settings.GetKeysConfig()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		preset := config.Preset
+		if preset == "" {
+			preset = tui.DefaultKeyPreset
+		}
+
+		fmt.Printf("Preset: %s\n", preset)
+		for _, section := range []string{tui.FormKeys, tui.FormConfigKeys, tui.ListKeys} {
+			fmt.Printf("\n%s:\n", section)
+			for _, binding := range tui.KeyBindings(section) {
+				fmt.Printf("  %-16s %s\n", binding[0], binding[1])
+			}
+		}
+	},
+}
+
+var keysUseCmd = &cobra.Command{
+	Use:         "use <preset>",
+	Short:       "Sets the key preset: default, vim or emacs",
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{skipClientAnnotation: "true"},
+	Run: func(cmd *cobra.Command, arguments []string) {
+		config, err := settings.GetKeysConfig()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		// the keys of the config must work with the new preset too
+		config.Preset = arguments[0]
+		if err := tui.SetKeys(config); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		if err := settings.SetKeyPreset(arguments[0]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		fmt.Printf("\n %s Key preset set to: %s\n\n", CheckMark, arguments[0])
+	},
+}
+
+// applies the configured key bindings, falls back to the default ones when they're invalid
+func loadKeys() {
+	config, err := settings.GetKeysConfig()
+	if err != nil {
+		// the config errors are reported by the command itself
+		return
+	}
+	if err := tui.SetKeys(config); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v, using the default keys\n", err)
+	}
+}
+
+func init() {
+	keysCmd.AddCommand(keysListCmd)
+	keysCmd.AddCommand(keysUseCmd)
+}
@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"fmt"
+	"sync"
 
+	"github.com/ChmaraX/notidb/internal/cache"
 	"github.com/ChmaraX/notidb/internal/notion"
 	"github.com/ChmaraX/notidb/internal/settings"
 	"github.com/ChmaraX/notidb/internal/tui"
@@ -10,17 +12,108 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// databases shared with the integration, with the titles of the pages above them by database ID
+type databaseList struct {
+	dbs   []notionapi.Database
+	paths map[string]string
+	// the pages after the loaded ones are streamed into the list, nil for cached databases
+	pages    *notion.Paginator[notionapi.Database]
+	hasMore  bool
+	resolver *notion.PathResolver
+	// the pages and the paths are loaded by the commands of the list, which run concurrently,
+	// mu guards the fields above, resolving the resolver
+	mu        sync.Mutex
+	resolving sync.Mutex
+}
+
+func newDatabaseList(dbs []notionapi.Database, paths map[string]string, pages *notion.Paginator[notionapi.Database]) *databaseList {
+	if paths == nil {
+		paths = make(map[string]string)
+	}
+	return &databaseList{dbs: dbs, paths: paths, pages: pages, hasMore: pages != nil, resolver: notion.NewPathResolver()}
+}
+
+// reports whether all pages of the databases are loaded
+func (l *databaseList) complete() bool {
+	return !l.hasMore
+}
+
+// loads the next page of databases, the list calls it once the previous page is added
+func (l *databaseList) loadMore() (tui.DbPage, error) {
+	dbs, err := l.pages.Next()
+	if err != nil {
+		return tui.DbPage{}, fmt.Errorf("error loading databases: %v", err)
+	}
+	page := tui.DbPage{Dbs: dbs, HasMore: l.pages.HasNext()}
+	l.addPage(page)
+	return page, nil
+}
+
+// resolves the paths of the databases for the list, the ones which failed
+// to resolve are left out and resolved again when the list is shown next time
+func (l *databaseList) resolvePaths(dbs []notionapi.Database) map[string]string {
+	l.resolving.Lock()
+	paths := l.resolver.Paths(dbs)
+	l.resolving.Unlock()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for id, path := range paths {
+		l.paths[id] = path
+	}
+	l.save()
+	return paths
+}
+
+func (l *databaseList) addPage(page tui.DbPage) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.dbs = append(l.dbs, page.Dbs...)
+	l.hasMore = page.HasMore
+	l.save()
+}
+
+// the databases are cached once all pages are loaded, with the paths resolved so far,
+// cached databases aren't saved again as that would extend their TTL
+func (l *databaseList) save() {
+	if l.complete() && l.pages != nil {
+		// the cache only saves requests, failing to write it isn't an error
+		_ = cache.SaveDatabases(cache.Databases{Dbs: l.dbs, Paths: l.paths})
+	}
+}
+
+// options of the list which stream the pages left and resolve the paths missing
+func (l *databaseList) listOptions(opts tui.DbListOptions) tui.DbListOptions {
+	opts.Paths = make(map[string]string)
+	for id, path := range l.paths {
+		opts.Paths[id] = path
+	}
+	opts.ResolvePaths = l.resolvePaths
+	if !l.complete() {
+		opts.LoadMore = l.loadMore
+	}
+	return opts
+}
+
+// loads the cached databases or the first page of them, the pages left and the paths are loaded by the list
 func loadDatabases() tui.Response {
-	databases, err := notion.GetAllNotionDbs()
 	id := "dbs"
+	if cached, ok := cache.GetDatabases(); ok && len(cached.Dbs) > 0 {
+		return tui.Response{Id: id, Data: newDatabaseList(cached.Dbs, cached.Paths, nil), Err: nil}
+	}
 
-	if err != nil {
-		return tui.Response{Id: id, Data: nil, Err: fmt.Errorf("error loading databases: %v", err)}
+	list := newDatabaseList(nil, nil, notion.SearchDatabases(""))
+
+	// a page can have no databases when the search returns other objects too
+	for len(list.dbs) == 0 && list.hasMore {
+		if _, err := list.loadMore(); err != nil {
+			return tui.Response{Id: id, Data: nil, Err: err}
+		}
 	}
-	if len(databases) == 0 {
+	if len(list.dbs) == 0 {
 		return tui.Response{Id: id, Data: nil, Err: fmt.Errorf("no databases found in your workspace or the access is not granted")}
 	}
-	return tui.Response{Id: id, Data: databases, Err: nil}
+	return tui.Response{Id: id, Data: list, Err: nil}
 }
 
 func loadDefaultDatabase() tui.Response {
@@ -42,27 +135,77 @@ func dbExists(dbs []notionapi.Database, dbId string) bool {
 	return false
 }
 
+// loads the first page of databases together with the default database ID
+func loadDatabasesWithDefault() (*databaseList, string, error) {
+	m := tui.NewLoadingModel("Calling Notion API - loading databases", loadDatabases, loadDefaultDatabase)
+	res := m.GetResponse("dbs")
+
+	if res.Err != nil {
+		return nil, "", res.Err
+	}
+
+	dbs := res.Data.(*databaseList)
+	defaultDbId := m.GetResponse("defaultDb").Data.(string)
+
+	return dbs, defaultDbId, nil
+}
+
+// sets the default database without the list, the database must be shared with the integration
+func setDefaultDbFromRef(ref string) (notionapi.Database, error) {
+	dbId, err := resolveDatabaseRef(ref)
+	if err != nil {
+		return notionapi.Database{}, err
+	}
+
+	db, err := getDatabase(dbId)
+	if err != nil {
+		return notionapi.Database{}, err
+	}
+
+	if err := settings.SetDefaultDatabase(string(db.ID)); err != nil {
+		return notionapi.Database{}, fmt.Errorf("error setting default database: %v", err)
+	}
+	return db, nil
+}
+
 var setDefaultDbCmd = &cobra.Command{
 	Use:     "set-db",
 	Aliases: []string{"sd"},
 	Short:   "Set default database",
-	Run: func(cmd *cobra.Command, args []string) {
-		m := tui.NewLoadingModel("Calling Notion API - loading databases", loadDatabases, loadDefaultDatabase)
-		res := m.GetResponse("dbs")
-
-		if res.Err != nil {
-			fmt.Printf("\n%s\n", res.Err)
+	Run: func(cmd *cobra.Command, arguments []string) {
+		// database given by the --db flag, no need for the list
+		if args.dbId != "" {
+			db, err := setDefaultDbFromRef(args.dbId)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			fmt.Printf("\n %s Default database successfully set to: %s\n\n", CheckMark, notion.DatabaseTitle(db))
 			return
 		}
 
-		dbs := res.Data.([]notionapi.Database)
-		defaultDbId := m.GetResponse("defaultDb").Data.(string)
+		dbs, defaultDbId, err := loadDatabasesWithDefault()
+		if err != nil {
+			fmt.Printf("\n%s\n", err)
+			return
+		}
 
-		if !dbExists(dbs, defaultDbId) && defaultDbId != settings.NoDefaultDatabaseId {
-			fmt.Printf("database which is set as default (%s) was not found in your workspace or the access is not granted", defaultDbId)
+		// the list reports it when the default database isn't in the pages left
+		if dbs.complete() && !dbExists(dbs.dbs, defaultDbId) && defaultDbId != settings.NoDefaultDatabaseId {
+			fmt.Printf("database which is set as default (%s) was not found in your workspace or the access is not granted, use --refresh if it was shared recently", defaultDbId)
 			return
 		}
 
-		tui.InitDbListModel(dbs, defaultDbId)
+		quitMsg := "No changes made."
+		if defaultDbId == settings.NoDefaultDatabaseId {
+			quitMsg = "No default database set."
+		}
+
+		tui.InitDbListModel(dbs.dbs, defaultDbId, dbs.listOptions(tui.DbListOptions{
+			Title:      "Choose default database for operations:",
+			SuccessMsg: "Default database successfully set to",
+			QuitMsg:    quitMsg,
+			OnSelect:   settings.SetDefaultDatabase,
+		}))
 	},
 }
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ChmaraX/notidb/internal/cache"
+	"github.com/ChmaraX/notidb/internal/settings"
+	"github.com/spf13/cobra"
+)
+
+var profileCmd = &cobra.Command{
+	Use:         "profile",
+	Aliases:     []string{"p"},
+	Short:       "Manage profiles, each with its own API key and settings",
+	Annotations: map[string]string{skipClientAnnotation: "true"},
+}
+
+var profileAddCmd = &cobra.Command{
+	Use:         "add <name>",
+	Short:       "Adds a new profile and initializes it",
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{skipClientAnnotation: "true"},
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+
+		if err := settings.AddProfile(name); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		// run init for the new profile so it gets its own API key and default database,
+		// the profile is removed again when init fails or is interrupted
+		settings.SetProfileOverride(name)
+		runInit(cmd, nil, func() { removeFailedProfile(name) })
+
+		fmt.Printf("Use it with `notidb --profile %s` or make it active with `notidb profile use %s`\n", name, name)
+	},
+}
+
+var profileListCmd = &cobra.Command{
+	Use:         "list",
+	Aliases:     []string{"ls"},
+	Short:       "Lists all profiles",
+	Annotations: map[string]string{skipClientAnnotation: "true"},
+	Run: func(cmd *cobra.Command, args []string) {
+		profiles, err := settings.ListProfiles()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		current, err := settings.CurrentProfile()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		for _, name := range profiles {
+			if name == current {
+				fmt.Printf("* %s\n", name)
+			} else {
+				fmt.Printf("  %s\n", name)
+			}
+		}
+	},
+}
+
+var profileUseCmd = &cobra.Command{
+	Use:         "use <name>",
+	Short:       "Sets the active profile",
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{skipClientAnnotation: "true"},
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := settings.UseProfile(args[0]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		fmt.Printf("\n %s Active profile set to: %s\n\n", CheckMark, args[0])
+	},
+}
+
+var profileRemoveCmd = &cobra.Command{
+	Use:         "remove <name>",
+	Aliases:     []string{"rm"},
+	Short:       "Removes a profile together with its API key",
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{skipClientAnnotation: "true"},
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+
+		// the keyring is opened first as it depends on the profile settings
+		settings.SetProfileOverride(name)
+		keyring, err := newKeyringManager()
+		if err != nil {
+			fmt.Printf("Error initializing keyring: %v\n", err)
+			return
+		}
+
+		if err := settings.RemoveProfile(name); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		if err := keyring.RemoveAPIKey(); err != nil {
+			fmt.Printf("Error removing API key: %v\n", err)
+			return
+		}
+		if err := cache.ClearProfile(); err != nil {
+			fmt.Printf("Error clearing cache: %v\n", err)
+			return
+		}
+
+		fmt.Printf("\n %s Profile removed: %s\n\n", CheckMark, name)
+	},
+}
+
+// removes the profile added by `profile add` together with the API key init may have saved
+func removeFailedProfile(name string) {
+	if keyring, err := newKeyringManager(); err == nil {
+		_ = keyring.RemoveAPIKey()
+	}
+	if err := settings.RemoveProfile(name); err != nil {
+		fmt.Printf("Error removing profile %s: %v\n", name, err)
+		return
+	}
+	fmt.Printf("Profile %s was not added\n", name)
+}
+
+// profiles created before the name rule keep working, their users are only told to replace them
+func warnInvalidProfile() {
+	name, err := settings.CurrentProfile()
+	if err != nil || name == "" {
+		// the config errors are reported by the command itself
+		return
+	}
+	if err := settings.ValidateProfileName(name); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v, add a profile with a valid name and remove this one with `notidb profile remove`\n", err)
+	}
+}
+
+func init() {
+	addInitFlags(profileAddCmd)
+
+	profileCmd.AddCommand(profileAddCmd)
+	profileCmd.AddCommand(profileListCmd)
+	profileCmd.AddCommand(profileUseCmd)
+	profileCmd.AddCommand(profileRemoveCmd)
+}
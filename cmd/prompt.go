@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ChmaraX/notidb/internal/tui"
+)
+
+// asks a yes/no question on the terminal, yes is the default
+func confirm(question string) bool {
+	fmt.Printf("%s [Y/n] ", question)
+
+	answer, err := tui.ReadLine()
+	if err != nil {
+		return false
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "", "y", "yes":
+		return true
+	}
+	return false
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/ChmaraX/notidb/internal/cache"
+	"github.com/ChmaraX/notidb/internal/settings"
+	"github.com/ChmaraX/notidb/internal/tui"
 	"github.com/spf13/cobra"
 )
 
@@ -13,7 +16,19 @@ const (
   notidb add
   notidb add --title "Book Idea" --content "A book about the history of the internet"
   notidb a -t "Book Idea" -c "A book about the history of the internet"
-  notidb "Book Idea" "A book about the history of the internet"`
+  notidb "Book Idea" "A book about the history of the internet"
+  notidb --profile work add
+  notidb add --db tasks "Book Idea"
+  notidb search --type page "Book Idea"`
+)
+
+// commands annotated with this key don't need the Notion client
+const skipClientAnnotation = "skipClient"
+
+var (
+	profile string
+	plain   bool
+	refresh bool
 )
 
 var rootCmd = &cobra.Command{
@@ -23,16 +38,37 @@ var rootCmd = &cobra.Command{
 	Version:       "0.0.1",
 	SilenceErrors: true,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		if cmd.Use != "init" {
+		settings.SetProfileOverride(profile)
+		tui.SetPlain(plain)
+		cache.SetRefresh(refresh)
+		warnInvalidProfile()
+		loadTheme()
+		loadKeys()
+		if _, ok := cmd.Annotations[skipClientAnnotation]; !ok {
 			initNotionClient()
 		}
 	},
 }
 
 func init() {
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "Profile to use instead of the active one (env: "+settings.ProfileEnvVar+")")
+	rootCmd.PersistentFlags().BoolVar(&plain, "plain", false, "Use line-based prompts and log lines instead of the full-screen TUI (env: "+tui.PlainEnvVar+")")
+	rootCmd.PersistentFlags().BoolVar(&refresh, "refresh", false, "Load databases and schemas from Notion instead of the cache (TTL env: "+cache.TTLEnvVar+")")
+	rootCmd.PersistentFlags().StringVarP(&args.dbId, "db", "d", "", "Database to use instead of the default one (alias, ID, URL or title)")
+
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(setDefaultDbCmd)
 	rootCmd.AddCommand(addEntryCmd)
+	rootCmd.AddCommand(profileCmd)
+	rootCmd.AddCommand(aliasCmd)
+	rootCmd.AddCommand(authCmd)
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(templateCmd)
+	rootCmd.AddCommand(formCmd)
+	rootCmd.AddCommand(themeCmd)
+	rootCmd.AddCommand(keysCmd)
+	rootCmd.AddCommand(searchCmd)
+	rootCmd.AddCommand(cacheCmd)
 }
 
 func Execute() {
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ChmaraX/notidb/internal/notion"
+	"github.com/ChmaraX/notidb/internal/tui"
+	"github.com/jomei/notionapi"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+type searchArgs struct {
+	// page or database, empty for both
+	objectType string
+	// page or database the results are directly in
+	parent string
+	// print the first result or all of them as JSON instead of the list
+	first  bool
+	asJSON bool
+}
+
+var searchFlags searchArgs
+
+// returns the object type of the --type flag, empty for both types
+func parseObjectType(name string) (notionapi.ObjectType, error) {
+	switch name {
+	case "", "all":
+		return "", nil
+	case string(notionapi.ObjectTypePage), string(notionapi.ObjectTypeDatabase):
+		return notionapi.ObjectType(name), nil
+	}
+	return "", fmt.Errorf("unknown type %q, must be page or database", name)
+}
+
+// returns the ID of the parent given by ID or URL, databases can be given by alias or title too
+func resolveParentRef(ref string) (string, error) {
+	if id, ok := notion.ParseID(ref); ok {
+		return id, nil
+	}
+	return resolveDatabaseRef(ref)
+}
+
+// loads the first page with results, the pages left are loaded by the list
+func searchWorkspace(results *notion.Paginator[notion.SearchResult]) tui.Response {
+	id := "search"
+
+	// the results of a page can be filtered out by the parent
+	var found []notion.SearchResult
+	for len(found) == 0 && results.HasNext() {
+		page, err := results.Next()
+		if err != nil {
+			return tui.Response{Id: id, Data: nil, Err: fmt.Errorf("error searching: %v", err)}
+		}
+		found = page
+	}
+	return tui.Response{Id: id, Data: found, Err: nil}
+}
+
+var searchCmd = &cobra.Command{
+	Use:   "search <text>",
+	Short: "Searches pages and databases of the workspace by title and prints the URL of the chosen one",
+	Example: `  notidb search meeting notes
+  notidb search --type database tasks
+  notidb search --parent https://www.notion.so/Projects-0123456789abcdef0123456789abcdef roadmap`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, arguments []string) {
+		query := strings.Join(arguments, " ")
+
+		objectType, err := parseObjectType(searchFlags.objectType)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		var parentId string
+		if searchFlags.parent != "" {
+			if parentId, err = resolveParentRef(searchFlags.parent); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+		}
+
+		results := notion.SearchWorkspace(query, objectType, parentId)
+		m := tui.NewLoadingModel("Calling Notion API - searching", func() tui.Response {
+			return searchWorkspace(results)
+		})
+		res := m.GetResponse("search")
+		if res.Err != nil {
+			fmt.Printf("\n%s\n", res.Err)
+			return
+		}
+
+		found := res.Data.([]notion.SearchResult)
+		// all results are printed when there is no one to choose from the list
+		printAll := !term.IsTerminal(int(os.Stdin.Fd())) && !searchFlags.first
+		if searchFlags.asJSON || printAll {
+			rest, err := results.All()
+			if err != nil {
+				fmt.Printf("Error searching: %v\n", err)
+				return
+			}
+			found = append(found, rest...)
+		}
+
+		if searchFlags.asJSON {
+			if found == nil {
+				found = []notion.SearchResult{}
+			}
+			data, err := json.MarshalIndent(found, "", "  ")
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			fmt.Println(string(data))
+			return
+		}
+		if len(found) == 0 {
+			fmt.Printf("No pages or databases match %q, or the access is not granted.\n", query)
+			return
+		}
+
+		if searchFlags.first {
+			fmt.Printf("%s\n%s\n", found[0].URL, found[0].ID)
+			return
+		}
+		if printAll {
+			for _, result := range found {
+				fmt.Printf("%s\t%s\t%s\n", result.URL, result.ID, result.Title)
+			}
+			return
+		}
+
+		opts := tui.SearchListOptions{Title: fmt.Sprintf("Results for %q:", query)}
+		if results.HasNext() {
+			opts.LoadMore = func() ([]notion.SearchResult, bool, error) {
+				page, err := results.Next()
+				if err != nil {
+					return nil, false, fmt.Errorf("error searching: %v", err)
+				}
+				return page, results.HasNext(), nil
+			}
+		}
+
+		choice, ok := tui.InitSearchList(found, opts)
+		if !ok {
+			return
+		}
+		fmt.Printf("%s\n%s\n", choice.URL, choice.ID)
+	},
+}
+
+func init() {
+	searchCmd.Flags().StringVar(&searchFlags.objectType, "type", "", "Type of the results: page or database")
+	searchCmd.Flags().StringVar(&searchFlags.parent, "parent", "", "Page or database the results are directly in (ID or URL, databases also by alias or title)")
+	searchCmd.Flags().BoolVar(&searchFlags.first, "first", false, "Print the URL and the ID of the first result without the list")
+	searchCmd.Flags().BoolVar(&searchFlags.asJSON, "json", false, "Print all results as JSON without the list")
+	searchCmd.MarkFlagsMutuallyExclusive("first", "json")
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ChmaraX/notidb/internal/settings"
+	"github.com/ChmaraX/notidb/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Manage templates of the database used with `notidb add --template`",
+}
+
+var templateSaveCmd = &cobra.Command{
+	Use:   "save <name>",
+	Short: "Saves a template filled in the form, values and content can contain placeholders like {{today}}",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, arguments []string) {
+		name := arguments[0]
+
+		if err := args.validateDefaultDb(); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		schema, err := getDatabaseSchema(args.dbId)
+		if err != nil {
+			fmt.Printf("Error getting DB schema: %v\n", err)
+			return
+		}
+
+		dbConfig, err := settings.GetDatabaseConfig(args.dbId, false)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		// editing an existing template starts from its current values
+		values := tui.FormValues{}
+		if template, ok := dbConfig.Templates[name]; ok && template != nil {
+			values = tui.FormValues{Props: template.Values, Body: template.Body}
+		}
+
+		values, ok := tui.InitTemplateForm(schema, tui.FormOptions{Values: values, Layout: dbConfig.Form})
+		if !ok {
+			fmt.Println("No changes made.")
+			return
+		}
+
+		if err := settings.SaveTemplate(args.dbId, name, settings.Template{Values: values.Props, Body: values.Body}); err != nil {
+			fmt.Printf("Error saving template: %v\n", err)
+			return
+		}
+		fmt.Printf("\n %s Template %s saved, use it with `notidb add --template %s`\n\n", CheckMark, name, name)
+	},
+}
+
+var templateListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "Lists templates of the database",
+	Run: func(cmd *cobra.Command, arguments []string) {
+		if err := args.validateDefaultDb(); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		dbConfig, err := settings.GetDatabaseConfig(args.dbId, false)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		if len(dbConfig.Templates) == 0 {
+			fmt.Println("No templates saved, add one with `notidb template save <name>`")
+			return
+		}
+
+		names := make([]string, 0, len(dbConfig.Templates))
+		for name := range dbConfig.Templates {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			fmt.Println(name)
+		}
+	},
+}
+
+var templateRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Aliases: []string{"rm"},
+	Short:   "Removes a template of the database",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, arguments []string) {
+		name := arguments[0]
+
+		if err := args.validateDefaultDb(); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		if err := settings.RemoveTemplate(args.dbId, name); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		fmt.Printf("\n %s Template %s removed\n\n", CheckMark, name)
+	},
+}
+
+func init() {
+	templateCmd.AddCommand(templateSaveCmd)
+	templateCmd.AddCommand(templateListCmd)
+	templateCmd.AddCommand(templateRemoveCmd)
+}
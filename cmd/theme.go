@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ChmaraX/notidb/internal/settings"
+	"github.com/ChmaraX/notidb/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+// marks printed with success, failure and warning messages, colored by the theme
+var CheckMark, CrossMark, WarningMark = tui.Marks()
+
+var themeCmd = &cobra.Command{
+	Use:         "theme",
+	Short:       "Manage the color theme of the TUI",
+	Annotations: map[string]string{skipClientAnnotation: "true"},
+}
+
+var themeListCmd = &cobra.Command{
+	Use:         "list",
+	Aliases:     []string{"ls"},
+	Short:       "Lists the built-in and user-defined themes",
+	Annotations: map[string]string{skipClientAnnotation: "true"},
+	Run: func(cmd *cobra.Command, arguments []string) {
+		current, custom, err := settings.GetTheme()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		if current == "" {
+			current = tui.AutoTheme
+		}
+
+		for _, name := range tui.ThemeNames(custom) {
+			if name == current {
+				fmt.Printf("* %s\n", name)
+			} else {
+				fmt.Printf("  %s\n", name)
+			}
+		}
+	},
+}
+
+var themeUseCmd = &cobra.Command{
+	Use:         "use <name>",
+	Short:       "Sets the theme, \"auto\" picks the dark or light one by the terminal background",
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{skipClientAnnotation: "true"},
+	Run: func(cmd *cobra.Command, arguments []string) {
+		name := arguments[0]
+
+		_, custom, err := settings.GetTheme()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		t, err := tui.ResolveTheme(name, custom)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		if err := settings.SetTheme(name); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		applyTheme(t)
+		fmt.Printf("\n %s Theme set to: %s\n\n", CheckMark, name)
+	},
+}
+
+// applies the configured theme, falls back to the detected one when the theme is invalid
+func loadTheme() {
+	name, custom, err := settings.GetTheme()
+	if err != nil {
+		// the config errors are reported by the command itself
+		applyTheme(mustResolveTheme(tui.AutoTheme, nil))
+		return
+	}
+
+	t, err := tui.ResolveTheme(name, custom)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v, using the default theme\n", err)
+		t = mustResolveTheme(tui.AutoTheme, nil)
+	}
+	applyTheme(t)
+}
+
+func mustResolveTheme(name string, custom map[string]*settings.ThemeConfig) tui.Theme {
+	t, _ := tui.ResolveTheme(name, custom)
+	return t
+}
+
+func applyTheme(t tui.Theme) {
+	tui.SetTheme(t)
+}
+
+func init() {
+	themeCmd.AddCommand(themeListCmd)
+	themeCmd.AddCommand(themeUseCmd)
+}
@@ -4,15 +4,18 @@ go 1.19
 
 require (
 	github.com/99designs/keyring v1.2.2
+	github.com/atotto/clipboard v0.1.4
 	github.com/charmbracelet/lipgloss v0.7.1
+	github.com/gofrs/flock v0.8.0
 	github.com/jomei/notionapi v1.12.9
+	github.com/muesli/termenv v0.15.1
 	github.com/spf13/cobra v1.8.0
 	golang.org/x/term v0.16.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/99designs/go-keychain v0.0.0-20191008050251-8e49817e8af4 // indirect
-	github.com/atotto/clipboard v0.1.4 // indirect
 	github.com/aymanbagabas/go-osc52/v2 v2.0.1 // indirect
 	github.com/containerd/console v1.0.4-0.20230313162750-1ae8d489ac81 // indirect
 	github.com/danieljoos/wincred v1.1.2 // indirect
@@ -28,7 +31,6 @@ require (
 	github.com/muesli/ansi v0.0.0-20211018074035-2e021307bc4b // indirect
 	github.com/muesli/cancelreader v0.2.2 // indirect
 	github.com/muesli/reflow v0.3.0 // indirect
-	github.com/muesli/termenv v0.15.1 // indirect
 	github.com/rivo/uniseg v0.2.0 // indirect
 	github.com/sahilm/fuzzy v0.1.0 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
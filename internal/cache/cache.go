@@ -0,0 +1,273 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ChmaraX/notidb/internal/settings"
+	"github.com/jomei/notionapi"
+)
+
+const (
+	cacheDir          = "cache"
+	databasesFileName = "databases.json"
+	// edit times of the recently edited databases, checked when the list has expired
+	editedFileName = "edited.json"
+	schemasDir     = "schemas"
+	DefaultTTL     = 24 * time.Hour
+	// duration like 1h or 30m, 0 disables the cache
+	TTLEnvVar = "NOTIDB_CACHE_TTL"
+)
+
+// cached entries are ignored for the current invocation, they are still updated
+var refresh bool
+
+func SetRefresh(requested bool) {
+	refresh = requested
+}
+
+// Databases are the databases shared with the integration, with the paths of the pages above them
+type Databases struct {
+	Dbs   []notionapi.Database `json:"dbs"`
+	Paths map[string]string    `json:"paths"`
+}
+
+type entry[T any] struct {
+	SavedAt time.Time `json:"saved_at"`
+	Data    T         `json:"data"`
+}
+
+// returns the TTL from the env var, the default one when it's not set or invalid
+func ttl() time.Duration {
+	if value := os.Getenv(TTLEnvVar); value != "" {
+		if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+			return d
+		}
+	}
+	return DefaultTTL
+}
+
+// the cache is kept per profile, the profiles can use different workspaces
+func profileDir() (string, error) {
+	appDir, err := settings.AppDir()
+	if err != nil {
+		return "", err
+	}
+	profile, err := settings.CurrentProfile()
+	if err != nil {
+		return "", err
+	}
+	if profile == "" {
+		profile = settings.DefaultProfile
+	}
+	// names from before the profile name rule could point outside of the cache dir
+	if settings.ValidateProfileName(profile) != nil {
+		sum := sha256.Sum256([]byte(profile))
+		profile = "profile-" + hex.EncodeToString(sum[:8])
+	}
+	return filepath.Join(appDir, cacheDir, profile), nil
+}
+
+// IDs are returned with and without dashes, the file name is without them
+func schemaFileName(dbId string) string {
+	return filepath.Join(schemasDir, strings.ReplaceAll(dbId, "-", "")+".json")
+}
+
+// returns the cached data when it's younger than the TTL, any error is a miss
+func load[T any](name string) (T, bool) {
+	maxAge := ttl()
+	if refresh || maxAge == 0 {
+		var empty T
+		return empty, false
+	}
+
+	e, err := read[T](name)
+	if err != nil || time.Since(e.SavedAt) > maxAge {
+		return e.Data, false
+	}
+	return e.Data, true
+}
+
+// returns the cached entry regardless of its age
+func read[T any](name string) (entry[T], error) {
+	var e entry[T]
+	dir, err := profileDir()
+	if err != nil {
+		return e, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return e, err
+	}
+	err = json.Unmarshal(data, &e)
+	return e, err
+}
+
+func save[T any](name string, data T) error {
+	if ttl() == 0 {
+		return nil
+	}
+
+	dir, err := profileDir()
+	if err != nil {
+		return err
+	}
+	filePath := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(filePath), settings.DirPermMode); err != nil {
+		return err
+	}
+
+	encoded, err := json.Marshal(entry[T]{SavedAt: time.Now(), Data: data})
+	if err != nil {
+		return err
+	}
+	return settings.WriteFileAtomic(filePath, encoded)
+}
+
+func remove(name string) error {
+	dir, err := profileDir()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(dir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// returns the cached databases, false when they are not cached or expired
+func GetDatabases() (Databases, bool) {
+	return load[Databases](databasesFileName)
+}
+
+// caches the databases together with their schemas, only the schemas edited since
+// they were cached are written, the ones of databases no longer listed are removed
+func SaveDatabases(dbs Databases) error {
+	if ttl() == 0 {
+		return nil
+	}
+	if err := save(databasesFileName, dbs); err != nil {
+		return err
+	}
+
+	listed := make(map[string]bool)
+	for _, db := range dbs.Dbs {
+		name := schemaFileName(string(db.ID))
+		listed[filepath.Base(name)] = true
+		if cached, err := read[notionapi.Database](name); err == nil && cached.Data.LastEditedTime.Equal(db.LastEditedTime) {
+			continue
+		}
+		if err := SaveDatabase(db); err != nil {
+			return err
+		}
+	}
+
+	dir, err := profileDir()
+	if err != nil {
+		return err
+	}
+	files, err := os.ReadDir(filepath.Join(dir, schemasDir))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for _, file := range files {
+		if !listed[file.Name()] {
+			if err := remove(filepath.Join(schemasDir, file.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// returns false when the cached schemas can't be trusted, as neither the list of databases
+// nor the edit times from RecentDatabases are younger than the TTL
+func DatabasesChecked() bool {
+	// the cache isn't used, there is nothing to check
+	if refresh || ttl() == 0 {
+		return true
+	}
+	if _, ok := GetDatabases(); ok {
+		return true
+	}
+	_, ok := load[map[string]time.Time](editedFileName)
+	return ok
+}
+
+// replaces the cached schemas edited since they were cached with the recently edited
+// databases, and records their edit times
+func SaveRecentDatabases(dbs []notionapi.Database) error {
+	if ttl() == 0 {
+		return nil
+	}
+
+	edited := make(map[string]time.Time, len(dbs))
+	for _, db := range dbs {
+		edited[string(db.ID)] = db.LastEditedTime
+		cached, err := read[notionapi.Database](schemaFileName(string(db.ID)))
+		if err != nil || !db.LastEditedTime.After(cached.Data.LastEditedTime) {
+			continue
+		}
+		if err := SaveDatabase(db); err != nil {
+			return err
+		}
+	}
+	return save(editedFileName, edited)
+}
+
+// returns the cached database with its schema, false when it's not cached or expired,
+// a newer version of the database from the cached list replaces it
+func GetDatabase(dbId string) (notionapi.Database, bool) {
+	db, ok := load[notionapi.Database](schemaFileName(dbId))
+	if !ok {
+		return db, false
+	}
+
+	// an expired list still tells which schemas are outdated
+	if dbs, err := read[Databases](databasesFileName); err == nil {
+		for _, listed := range dbs.Data.Dbs {
+			if sameId(string(listed.ID), dbId) && listed.LastEditedTime.After(db.LastEditedTime) {
+				return listed, true
+			}
+		}
+	}
+	return db, true
+}
+
+func sameId(a, b string) bool {
+	return strings.ReplaceAll(a, "-", "") == strings.ReplaceAll(b, "-", "")
+}
+
+func SaveDatabase(db notionapi.Database) error {
+	return save(schemaFileName(string(db.ID)), db)
+}
+
+// removes the database, e.g. when an entry is rejected because of an outdated schema
+func RemoveDatabase(dbId string) error {
+	return remove(schemaFileName(dbId))
+}
+
+// removes the cache of the current profile, e.g. when its API key is replaced,
+// as the key can belong to another workspace
+func ClearProfile() error {
+	dir, err := profileDir()
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+// removes the cache of all profiles
+func Clear() error {
+	appDir, err := settings.AppDir()
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(filepath.Join(appDir, cacheDir))
+}
@@ -0,0 +1,247 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ChmaraX/notidb/internal/settings"
+	"github.com/jomei/notionapi"
+)
+
+// points the cache to a temporary home dir, with the profile set by the flag
+func setupCache(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(TTLEnvVar, "")
+	settings.SetProfileOverride("test")
+	t.Cleanup(func() {
+		settings.SetProfileOverride("")
+		SetRefresh(false)
+	})
+	return filepath.Join(home, settings.NotiDBAppDir, cacheDir, "test")
+}
+
+// writes the cache entry as if it was saved at the given time
+func writeEntry[T any](t *testing.T, dir, name string, savedAt time.Time, data T) {
+	t.Helper()
+	encoded, err := json.Marshal(entry[T]{SavedAt: savedAt, Data: data})
+	if err != nil {
+		t.Fatal(err)
+	}
+	filePath := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(filePath), settings.DirPermMode); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filePath, encoded, settings.FilePermMode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readSavedAt(t *testing.T, dir, name string) time.Time {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var e entry[json.RawMessage]
+	if err := json.Unmarshal(data, &e); err != nil {
+		t.Fatal(err)
+	}
+	return e.SavedAt
+}
+
+func database(id string, edited time.Time) notionapi.Database {
+	return notionapi.Database{ID: notionapi.ObjectID(id), LastEditedTime: edited}
+}
+
+func TestLoadTTL(t *testing.T) {
+	tests := []struct {
+		name    string
+		ttl     string
+		refresh bool
+		age     time.Duration
+		want    bool
+	}{
+		{name: "default TTL", age: time.Hour, want: true},
+		{name: "expired with the default TTL", age: 25 * time.Hour, want: false},
+		{name: "custom TTL", ttl: "30m", age: 10 * time.Minute, want: true},
+		{name: "expired with a custom TTL", ttl: "30m", age: time.Hour, want: false},
+		{name: "invalid TTL uses the default one", ttl: "soon", age: time.Hour, want: true},
+		{name: "zero TTL disables the cache", ttl: "0", age: time.Second, want: false},
+		{name: "refresh ignores the cache", refresh: true, age: time.Second, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := setupCache(t)
+			t.Setenv(TTLEnvVar, tt.ttl)
+			SetRefresh(tt.refresh)
+			writeEntry(t, dir, databasesFileName, time.Now().Add(-tt.age), Databases{Dbs: []notionapi.Database{database("a", time.Now())}})
+
+			if _, ok := GetDatabases(); ok != tt.want {
+				t.Errorf("GetDatabases() ok = %v, want %v", ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestSaveDatabases(t *testing.T) {
+	edited := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cachedAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	tests := []struct {
+		name string
+		// databases with a cached schema, all edited at the same time
+		cached []string
+		listed []notionapi.Database
+		// schemas expected to be written by the save
+		written []string
+		kept    []string
+		removed []string
+	}{
+		{
+			name:    "unchanged schemas are not written",
+			cached:  []string{"a", "b"},
+			listed:  []notionapi.Database{database("a", edited), database("b", edited)},
+			kept:    []string{"a", "b"},
+			written: nil,
+		},
+		{
+			name:    "edited schema is written",
+			cached:  []string{"a", "b"},
+			listed:  []notionapi.Database{database("a", edited), database("b", edited.Add(time.Hour))},
+			kept:    []string{"a"},
+			written: []string{"b"},
+		},
+		{
+			name:    "new schema is written",
+			cached:  []string{"a"},
+			listed:  []notionapi.Database{database("a", edited), database("b", edited)},
+			kept:    []string{"a"},
+			written: []string{"b"},
+		},
+		{
+			name:    "schema of an unlisted database is removed",
+			cached:  []string{"a", "b"},
+			listed:  []notionapi.Database{database("a", edited)},
+			kept:    []string{"a"},
+			removed: []string{"b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := setupCache(t)
+			for _, id := range tt.cached {
+				writeEntry(t, dir, schemaFileName(id), cachedAt, database(id, edited))
+			}
+
+			if err := SaveDatabases(Databases{Dbs: tt.listed}); err != nil {
+				t.Fatalf("SaveDatabases() error = %v", err)
+			}
+
+			for _, id := range tt.kept {
+				if savedAt := readSavedAt(t, dir, schemaFileName(id)); !savedAt.Equal(cachedAt) {
+					t.Errorf("schema %s was written", id)
+				}
+			}
+			for _, id := range tt.written {
+				if savedAt := readSavedAt(t, dir, schemaFileName(id)); !savedAt.After(cachedAt) {
+					t.Errorf("schema %s was not written", id)
+				}
+			}
+			for _, id := range tt.removed {
+				if _, err := os.Stat(filepath.Join(dir, schemaFileName(id))); !errors.Is(err, os.ErrNotExist) {
+					t.Errorf("schema %s was not removed", id)
+				}
+			}
+		})
+	}
+}
+
+func TestGetDatabase(t *testing.T) {
+	edited := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		// age of the cached list, no list is cached when it's zero
+		listAge    time.Duration
+		listEdited time.Time
+		recent     []notionapi.Database
+		want       time.Time
+		wantCheck  bool
+	}{
+		{
+			name:       "cached schema",
+			listAge:    time.Hour,
+			listEdited: edited,
+			want:       edited,
+			wantCheck:  true,
+		},
+		{
+			name:       "newer database from the list",
+			listAge:    time.Hour,
+			listEdited: edited.Add(time.Hour),
+			want:       edited.Add(time.Hour),
+			wantCheck:  true,
+		},
+		{
+			name:       "newer database from an expired list",
+			listAge:    48 * time.Hour,
+			listEdited: edited.Add(time.Hour),
+			want:       edited.Add(time.Hour),
+			wantCheck:  false,
+		},
+		{
+			name:      "no list",
+			want:      edited,
+			wantCheck: false,
+		},
+		{
+			name:      "recently edited database",
+			recent:    []notionapi.Database{database("a", edited.Add(time.Hour))},
+			want:      edited.Add(time.Hour),
+			wantCheck: true,
+		},
+		{
+			name:      "recently edited databases without changes",
+			recent:    []notionapi.Database{database("a", edited), database("b", edited)},
+			want:      edited,
+			wantCheck: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := setupCache(t)
+			writeEntry(t, dir, schemaFileName("a"), time.Now(), database("a", edited))
+			if tt.listAge > 0 {
+				writeEntry(t, dir, databasesFileName, time.Now().Add(-tt.listAge), Databases{Dbs: []notionapi.Database{database("a", tt.listEdited)}})
+			}
+			if tt.recent != nil {
+				if err := SaveRecentDatabases(tt.recent); err != nil {
+					t.Fatalf("SaveRecentDatabases() error = %v", err)
+				}
+			}
+
+			if got := DatabasesChecked(); got != tt.wantCheck {
+				t.Errorf("DatabasesChecked() = %v, want %v", got, tt.wantCheck)
+			}
+			db, ok := GetDatabase("a")
+			if !ok {
+				t.Fatalf("GetDatabase() found no database")
+			}
+			if !db.LastEditedTime.Equal(tt.want) {
+				t.Errorf("GetDatabase() edited at %v, want %v", db.LastEditedTime, tt.want)
+			}
+			if _, err := os.Stat(filepath.Join(dir, schemaFileName("b"))); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("SaveRecentDatabases() cached a schema which wasn't cached before")
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package drafts
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ChmaraX/notidb/internal/settings"
+)
+
+const draftsDir = "drafts"
+
+// unsaved content of the add form, there is at most one draft per database
+type Draft struct {
+	Props   map[string]string `json:"props"`
+	Body    string            `json:"body"`
+	SavedAt time.Time         `json:"saved_at"`
+}
+
+func draftFilePath(dbId string) (string, error) {
+	appDir, err := settings.AppDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(appDir, draftsDir, dbId+".json"), nil
+}
+
+func Save(dbId string, draft Draft) error {
+	filePath, err := draftFilePath(dbId)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), settings.DirPermMode); err != nil {
+		return err
+	}
+
+	draft.SavedAt = time.Now()
+	data, err := json.MarshalIndent(draft, "", "  ")
+	if err != nil {
+		return err
+	}
+	return settings.WriteFileAtomic(filePath, data)
+}
+
+// returns the draft of the database, false when there is none
+func Load(dbId string) (Draft, bool, error) {
+	filePath, err := draftFilePath(dbId)
+	if err != nil {
+		return Draft{}, false, err
+	}
+
+	data, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return Draft{}, false, nil
+	}
+	if err != nil {
+		return Draft{}, false, err
+	}
+
+	var draft Draft
+	if err := json.Unmarshal(data, &draft); err != nil {
+		return Draft{}, false, err
+	}
+	return draft, true, nil
+}
+
+// removes the draft of the database, a missing draft is not an error
+func Remove(dbId string) error {
+	filePath, err := draftFilePath(dbId)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package drafts
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ChmaraX/notidb/internal/settings"
+)
+
+func TestDrafts(t *testing.T) {
+	const dbId = "01234567-89ab-cdef-0123-456789abcdef"
+
+	tests := []struct {
+		name string
+		// drafts saved one after another, the last one is expected back
+		saves []Draft
+		// content of the draft file, written instead of saving
+		file      string
+		remove    bool
+		wantDraft bool
+		wantErr   bool
+	}{
+		{
+			name:      "no draft",
+			wantDraft: false,
+		},
+		{
+			name:      "saved draft",
+			saves:     []Draft{{Props: map[string]string{"Name": "Idea"}, Body: "# Notes\n"}},
+			wantDraft: true,
+		},
+		{
+			name: "later save replaces the draft",
+			saves: []Draft{
+				{Props: map[string]string{"Name": "Idea"}},
+				{Props: map[string]string{"Name": "Better idea", "Status": "Open"}, Body: "text"},
+			},
+			wantDraft: true,
+		},
+		{
+			name:      "removed draft",
+			saves:     []Draft{{Body: "text"}},
+			remove:    true,
+			wantDraft: false,
+		},
+		{
+			name:      "removing a missing draft",
+			remove:    true,
+			wantDraft: false,
+		},
+		{
+			name:    "corrupt draft",
+			file:    "{",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+
+			for _, draft := range tt.saves {
+				if err := Save(dbId, draft); err != nil {
+					t.Fatalf("Save() error = %v", err)
+				}
+			}
+			if tt.file != "" {
+				filePath := filepath.Join(home, settings.NotiDBAppDir, draftsDir, dbId+".json")
+				if err := os.MkdirAll(filepath.Dir(filePath), settings.DirPermMode); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filePath, []byte(tt.file), settings.FilePermMode); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.remove {
+				if err := Remove(dbId); err != nil {
+					t.Fatalf("Remove() error = %v", err)
+				}
+			}
+
+			draft, ok, err := Load(dbId)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if ok != tt.wantDraft {
+				t.Fatalf("Load() found a draft = %v, want %v", ok, tt.wantDraft)
+			}
+			if !tt.wantDraft {
+				return
+			}
+
+			want := tt.saves[len(tt.saves)-1]
+			if !reflect.DeepEqual(draft.Props, want.Props) || draft.Body != want.Body {
+				t.Errorf("Load() = %+v, want %+v", draft, want)
+			}
+			if draft.SavedAt.IsZero() {
+				t.Errorf("Load() returned a draft without the time it was saved")
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package editor
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const frontMatterDelimiter = "---"
+
+// property value shown in the front matter, fields keep their order
+type Field struct {
+	Name  string
+	Value string
+}
+
+// returns the command opening the file in $VISUAL or $EDITOR, which can contain arguments like "code --wait"
+func Command(filePath string) *exec.Cmd {
+	parts := strings.Fields(os.Getenv("VISUAL"))
+	if len(parts) == 0 {
+		parts = strings.Fields(os.Getenv("EDITOR"))
+	}
+	// unset or blank variables fall back to the default editor
+	if len(parts) == 0 {
+		parts = []string{"vi"}
+		if runtime.GOOS == "windows" {
+			parts = []string{"notepad"}
+		}
+	}
+
+	cmd := exec.Command(parts[0], append(parts[1:], filePath)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd
+}
+
+// writes the content to a temp Markdown file, the caller removes it
+func WriteTempFile(content []byte) (string, error) {
+	file, err := os.CreateTemp("", "notidb-*.md")
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	if _, err := file.Write(content); err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+// reads the edited file and removes it
+func ReadTempFile(filePath string) ([]byte, error) {
+	defer os.Remove(filePath)
+	return os.ReadFile(filePath)
+}
+
+// opens the content in the editor and returns it once the editor exits
+func Edit(content []byte) ([]byte, error) {
+	filePath, err := WriteTempFile(content)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := Command(filePath).Run(); err != nil {
+		os.Remove(filePath)
+		return nil, fmt.Errorf("editor failed: %w", err)
+	}
+	return ReadTempFile(filePath)
+}
+
+// returns the body with the fields as YAML front matter, without front matter when there are no fields
+func Marshal(fields []Field, body string) ([]byte, error) {
+	var content bytes.Buffer
+
+	if len(fields) > 0 {
+		mapping := &yaml.Node{Kind: yaml.MappingNode}
+		for _, field := range fields {
+			mapping.Content = append(mapping.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: field.Name},
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: field.Value},
+			)
+		}
+
+		data, err := yaml.Marshal(mapping)
+		if err != nil {
+			return nil, err
+		}
+		content.WriteString(frontMatterDelimiter + "\n")
+		content.Write(data)
+		content.WriteString(frontMatterDelimiter + "\n")
+	}
+
+	content.WriteString(body)
+	return content.Bytes(), nil
+}
+
+// splits the content into the front matter values and the body
+func Unmarshal(content []byte) (map[string]string, string, error) {
+	text := strings.ReplaceAll(string(content), "\r\n", "\n")
+	values := make(map[string]string)
+
+	if !strings.HasPrefix(text, frontMatterDelimiter+"\n") {
+		return values, text, nil
+	}
+
+	rest := strings.TrimPrefix(text, frontMatterDelimiter+"\n")
+	end := strings.Index(rest, "\n"+frontMatterDelimiter)
+	var frontMatter, body string
+	switch {
+	case strings.HasPrefix(rest, frontMatterDelimiter):
+		// empty front matter
+		body = strings.TrimPrefix(rest, frontMatterDelimiter)
+	case end == -1:
+		return nil, "", fmt.Errorf("front matter is not closed with %s", frontMatterDelimiter)
+	default:
+		frontMatter = rest[:end]
+		body = rest[end+len("\n"+frontMatterDelimiter):]
+	}
+	body = strings.TrimPrefix(body, "\n")
+
+	if err := yaml.Unmarshal([]byte(frontMatter), &values); err != nil {
+		return nil, "", fmt.Errorf("invalid front matter: %w", err)
+	}
+	return values, body, nil
+}
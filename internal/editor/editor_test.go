@@ -0,0 +1,135 @@
+package editor
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestUnmarshal(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		wantValues map[string]string
+		wantBody   string
+		wantErr    string
+	}{
+		{
+			name:       "no front matter",
+			content:    "# Notes\ntext\n",
+			wantValues: map[string]string{},
+			wantBody:   "# Notes\ntext\n",
+		},
+		{
+			name:       "front matter",
+			content:    "---\nName: Idea\nStatus: Open\n---\nbody\n",
+			wantValues: map[string]string{"Name": "Idea", "Status": "Open"},
+			wantBody:   "body\n",
+		},
+		{
+			name:       "empty front matter",
+			content:    "---\n---\nbody",
+			wantValues: map[string]string{},
+			wantBody:   "body",
+		},
+		{
+			name:       "Windows line endings",
+			content:    "---\r\nName: Idea\r\n---\r\nbody\r\n",
+			wantValues: map[string]string{"Name": "Idea"},
+			wantBody:   "body\n",
+		},
+		{
+			name:       "quoted value",
+			content:    "---\nDate: \"01/02/2024\"\nTags: 'a, b'\n---\n",
+			wantValues: map[string]string{"Date": "01/02/2024", "Tags": "a, b"},
+			wantBody:   "",
+		},
+		{
+			name:       "delimiter in the body",
+			content:    "---\nName: Idea\n---\nabove\n---\nbelow\n",
+			wantValues: map[string]string{"Name": "Idea"},
+			wantBody:   "above\n---\nbelow\n",
+		},
+		{
+			name:    "front matter not closed",
+			content: "---\nName: Idea\nbody\n",
+			wantErr: "not closed",
+		},
+		{
+			name:    "invalid front matter",
+			content: "---\nName: [Idea\n---\n",
+			wantErr: "invalid front matter",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, body, err := Unmarshal([]byte(tt.content))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Unmarshal() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if !reflect.DeepEqual(values, tt.wantValues) {
+				t.Errorf("Unmarshal() values = %v, want %v", values, tt.wantValues)
+			}
+			if body != tt.wantBody {
+				t.Errorf("Unmarshal() body = %q, want %q", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestMarshalUnmarshal(t *testing.T) {
+	fields := []Field{{Name: "Name", Value: "Idea: the best"}, {Name: "Count", Value: "3"}, {Name: "Empty", Value: ""}}
+
+	content, err := Marshal(fields, "body\n")
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	values, body, err := Unmarshal(content)
+	if err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	want := map[string]string{"Name": "Idea: the best", "Count": "3", "Empty": ""}
+	if !reflect.DeepEqual(values, want) || body != "body\n" {
+		t.Errorf("Unmarshal(Marshal()) = %v, %q, want %v, %q", values, body, want, "body\n")
+	}
+}
+
+func TestCommand(t *testing.T) {
+	defaultEditor := "vi"
+	if runtime.GOOS == "windows" {
+		defaultEditor = "notepad"
+	}
+
+	tests := []struct {
+		name   string
+		visual string
+		editor string
+		want   []string
+	}{
+		{name: "default editor", want: []string{defaultEditor, "file.md"}},
+		{name: "EDITOR", editor: "nano", want: []string{"nano", "file.md"}},
+		{name: "VISUAL before EDITOR", visual: "code --wait", editor: "nano", want: []string{"code", "--wait", "file.md"}},
+		{name: "blank VISUAL", visual: "  ", editor: "nano", want: []string{"nano", "file.md"}},
+		{name: "blank EDITOR", editor: " \t", want: []string{defaultEditor, "file.md"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("VISUAL", tt.visual)
+			t.Setenv("EDITOR", tt.editor)
+
+			if got := Command("file.md").Args; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Command() args = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
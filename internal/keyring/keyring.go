@@ -1,36 +1,95 @@
 package keyring
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/99designs/keyring"
 )
 
 const KeyringServiceName = "notidb"
 const KeychainKey = "notidb"
+const defaultProfile = "default"
+
+const (
+	BackendEnvVar    = "NOTIDB_KEYRING_BACKEND"
+	PassphraseEnvVar = "NOTIDB_KEYRING_PASSPHRASE"
+)
+
+// backends selectable by the user, "auto" uses the system keyring
+var backends = map[string][]keyring.BackendType{
+	"auto":           {keyring.KeychainBackend, keyring.SecretServiceBackend},
+	"keychain":       {keyring.KeychainBackend},
+	"secret-service": {keyring.SecretServiceBackend},
+	"pass":           {keyring.PassBackend},
+	"file":           {keyring.FileBackend},
+}
+
+type Options struct {
+	Profile string
+	// one of the backends above, empty means the NOTIDB_KEYRING_BACKEND env var or "auto"
+	Backend string
+	// directory of the encrypted file backend
+	FileDir string
+}
 
 type KeyringManager struct {
 	ring keyring.Keyring
+	key  string
 }
 
-func NewKeyringManager() (*KeyringManager, error) {
+func NewKeyringManager(opts Options) (*KeyringManager, error) {
+	backend := opts.Backend
+	if env := os.Getenv(BackendEnvVar); env != "" {
+		backend = env
+	}
+	if backend == "" {
+		backend = "auto"
+	}
+
+	allowedBackends, ok := backends[backend]
+	if !ok {
+		return nil, fmt.Errorf("unknown keyring backend %q, use one of: auto, keychain, secret-service, pass, file", backend)
+	}
+
 	ring, err := keyring.Open(keyring.Config{
-		ServiceName: KeyringServiceName,
-		AllowedBackends: []keyring.BackendType{
-			keyring.KeychainBackend,
-			keyring.SecretServiceBackend,
-		},
-		KeychainName: "login",
+		ServiceName:      KeyringServiceName,
+		AllowedBackends:  allowedBackends,
+		KeychainName:     "login",
+		FileDir:          opts.FileDir,
+		FilePasswordFunc: filePassphrase,
+		PassPrefix:       KeyringServiceName,
 	})
 
+	if err == keyring.ErrNoAvailImpl {
+		return nil, fmt.Errorf("keyring backend %q is not available on this system, use the file or pass backend (%s) or the %s env var", backend, BackendEnvVar, APIKeyEnvVar)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return &KeyringManager{ring: ring}, nil
+	return &KeyringManager{ring: ring, key: profileKey(opts.Profile)}, nil
+}
+
+// passphrase of the file backend, read from the env var so it works without a terminal
+func filePassphrase(prompt string) (string, error) {
+	if passphrase, ok := os.LookupEnv(PassphraseEnvVar); ok {
+		return passphrase, nil
+	}
+	return keyring.TerminalPrompt(prompt)
+}
+
+// the default profile keeps the original key so existing setups keep working
+func profileKey(profile string) string {
+	if profile == "" || profile == defaultProfile {
+		return KeychainKey
+	}
+	return KeychainKey + ":" + profile
 }
 
 func (a *KeyringManager) SaveAPIKey(key string) error {
 	err := a.ring.Set(keyring.Item{
-		Key:  KeychainKey,
+		Key:  a.key,
 		Data: []byte(key),
 	})
 
@@ -42,10 +101,19 @@ func (a *KeyringManager) SaveAPIKey(key string) error {
 }
 
 func (a *KeyringManager) GetAPIKey() (string, error) {
-	item, err := a.ring.Get(KeychainKey)
+	item, err := a.ring.Get(a.key)
 	if err != nil {
 		return "", err
 	}
 
 	return string(item.Data), nil
 }
+
+// removes the API key, a missing key is not an error
+func (a *KeyringManager) RemoveAPIKey() error {
+	err := a.ring.Remove(a.key)
+	if err != nil && err != keyring.ErrKeyNotFound && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package keyring
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+const (
+	APIKeyEnvVar      = "NOTIDB_API_KEY"
+	NotionTokenEnvVar = "NOTION_TOKEN"
+)
+
+// returns the API key from the environment and the name of the variable it was read from
+func LookupEnvAPIKey() (string, string, bool) {
+	for _, name := range []string{APIKeyEnvVar, NotionTokenEnvVar} {
+		if key := strings.TrimSpace(os.Getenv(name)); key != "" {
+			return key, name, true
+		}
+	}
+	return "", "", false
+}
+
+// runs the command through the shell and returns its trimmed output as the API key
+func RunAPIKeyCommand(command string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stdin = os.Stdin
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("api key command failed: %v %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	key := strings.TrimSpace(string(out))
+	if key == "" {
+		return "", fmt.Errorf("api key command returned an empty output")
+	}
+	return key, nil
+}
@@ -0,0 +1,112 @@
+package notion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jomei/notionapi"
+)
+
+// a single request creates at most 100 children of a block and two levels of nested blocks
+const (
+	maxRequestChildren = 100
+	maxRequestDepth    = 2
+)
+
+// block kept apart from its children, so they can be created by separate requests
+type blockTree struct {
+	block    copiedBlock
+	children []blockTree
+}
+
+// splits the blocks into trees, the children of any block type are found in its JSON
+func toBlockTrees(blocks []notionapi.Block) ([]blockTree, error) {
+	data, err := json.Marshal(blocks)
+	if err != nil {
+		return nil, err
+	}
+	var raw []interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	return rawBlockTrees(raw), nil
+}
+
+func rawBlockTrees(raw []interface{}) []blockTree {
+	trees := make([]blockTree, 0, len(raw))
+	for _, item := range raw {
+		fields, _ := item.(map[string]interface{})
+		blockType, _ := fields["type"].(string)
+		content, _ := fields[blockType].(map[string]interface{})
+		if content == nil {
+			content = make(map[string]interface{})
+		}
+
+		var children []blockTree
+		if rawChildren, ok := content["children"].([]interface{}); ok {
+			children = rawBlockTrees(rawChildren)
+		}
+		delete(content, "children")
+
+		trees = append(trees, blockTree{
+			block: copiedBlock{
+				BasicBlock: notionapi.BasicBlock{Object: notionapi.ObjectTypeBlock, Type: notionapi.BlockType(blockType)},
+				content:    content,
+			},
+			children: children,
+		})
+	}
+	return trees
+}
+
+// reports whether the trees at the given depth can be created by a single request
+func fitsInRequest(trees []blockTree, depth int) bool {
+	if len(trees) > maxRequestChildren {
+		return false
+	}
+	for _, tree := range trees {
+		if len(tree.children) == 0 {
+			continue
+		}
+		if depth == maxRequestDepth || !fitsInRequest(tree.children, depth+1) {
+			return false
+		}
+	}
+	return true
+}
+
+// appends the blocks in batches the API accepts, the children are appended
+// to the created blocks one level at a time
+func appendBlockTrees(parentId notionapi.BlockID, trees []blockTree) error {
+	for start := 0; start < len(trees); start += maxRequestChildren {
+		end := start + maxRequestChildren
+		if end > len(trees) {
+			end = len(trees)
+		}
+		batch := trees[start:end]
+
+		blocks := make([]notionapi.Block, len(batch))
+		for i, tree := range batch {
+			blocks[i] = tree.block
+		}
+		res, err := NotionClient.Block.AppendChildren(context.Background(), parentId, &notionapi.AppendBlockChildrenRequest{Children: blocks})
+		if err != nil {
+			return mapAPIError(err)
+		}
+
+		// the created blocks are returned in the order they were sent
+		for i, tree := range batch {
+			if len(tree.children) == 0 {
+				continue
+			}
+			if i >= len(res.Results) {
+				return fmt.Errorf("only %d of %d blocks were created", len(res.Results), len(batch))
+			}
+			if err := appendBlockTrees(res.Results[i].GetID(), tree.children); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package notion
+
+import (
+	"testing"
+
+	"github.com/jomei/notionapi"
+)
+
+func paragraph(children ...notionapi.Block) notionapi.Block {
+	return notionapi.ParagraphBlock{
+		BasicBlock: basicBlock(notionapi.BlockTypeParagraph),
+		Paragraph:  notionapi.Paragraph{RichText: createRichText("text"), Children: children},
+	}
+}
+
+func paragraphs(n int) []notionapi.Block {
+	blocks := make([]notionapi.Block, n)
+	for i := range blocks {
+		blocks[i] = paragraph()
+	}
+	return blocks
+}
+
+// counts the blocks of the trees, including the nested ones
+func countBlocks(trees []blockTree) int {
+	count := len(trees)
+	for _, tree := range trees {
+		count += countBlocks(tree.children)
+	}
+	return count
+}
+
+func TestBlockTrees(t *testing.T) {
+	copied := copiedBlock{
+		BasicBlock: basicBlock(notionapi.BlockTypeToggle),
+		content:    map[string]interface{}{"children": []notionapi.Block{paragraph(paragraph())}},
+	}
+
+	tests := []struct {
+		name   string
+		blocks []notionapi.Block
+		count  int
+		fits   bool
+	}{
+		{
+			name:   "no blocks",
+			blocks: nil,
+			count:  0,
+			fits:   true,
+		},
+		{
+			name:   "two levels of nesting",
+			blocks: []notionapi.Block{paragraph(paragraph(paragraph()))},
+			count:  3,
+			fits:   true,
+		},
+		{
+			name:   "three levels of nesting",
+			blocks: []notionapi.Block{paragraph(paragraph(paragraph(paragraph())))},
+			count:  4,
+			fits:   false,
+		},
+		{
+			name:   "copied block",
+			blocks: []notionapi.Block{copied},
+			count:  3,
+			fits:   true,
+		},
+		{
+			name:   "100 blocks",
+			blocks: paragraphs(100),
+			count:  100,
+			fits:   true,
+		},
+		{
+			name:   "101 blocks",
+			blocks: paragraphs(101),
+			count:  101,
+			fits:   false,
+		},
+		{
+			name:   "101 children",
+			blocks: []notionapi.Block{paragraph(paragraphs(101)...)},
+			count:  102,
+			fits:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trees, err := toBlockTrees(tt.blocks)
+			if err != nil {
+				t.Fatalf("toBlockTrees() error = %v", err)
+			}
+			if got := countBlocks(trees); got != tt.count {
+				t.Errorf("toBlockTrees() has %d blocks, want %d", got, tt.count)
+			}
+			for _, tree := range trees {
+				if _, ok := tree.block.content["children"]; ok {
+					t.Errorf("toBlockTrees() kept the children in the block content")
+				}
+			}
+			if got := fitsInRequest(trees, 0); got != tt.fits {
+				t.Errorf("fitsInRequest() = %v, want %v", got, tt.fits)
+			}
+		})
+	}
+}
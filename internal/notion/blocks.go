@@ -0,0 +1,127 @@
+package notion
+
+import (
+	"strings"
+
+	"github.com/jomei/notionapi"
+)
+
+// max length of a single rich text object accepted by the Notion API
+const maxRichTextLength = 2000
+
+var codeLanguageAliases = map[string]string{
+	"":       "plain text",
+	"text":   "plain text",
+	"golang": "go",
+	"js":     "javascript",
+	"ts":     "typescript",
+	"py":     "python",
+	"sh":     "shell",
+	"zsh":    "shell",
+	"yml":    "yaml",
+	"md":     "markdown",
+}
+
+// splits the content into rich text objects within the API length limit
+func createRichText(content string) []notionapi.RichText {
+	var richText []notionapi.RichText
+	runes := []rune(content)
+	for len(runes) > 0 {
+		n := maxRichTextLength
+		if len(runes) < n {
+			n = len(runes)
+		}
+		richText = append(richText, notionapi.RichText{
+			Type: "text",
+			Text: &notionapi.Text{Content: string(runes[:n])},
+		})
+		runes = runes[n:]
+	}
+	if richText == nil {
+		richText = []notionapi.RichText{}
+	}
+	return richText
+}
+
+func basicBlock(blockType notionapi.BlockType) notionapi.BasicBlock {
+	return notionapi.BasicBlock{
+		Object: "block",
+		Type:   blockType,
+	}
+}
+
+func CreateParagraphBlock(content string) notionapi.Block {
+	return notionapi.ParagraphBlock{
+		BasicBlock: basicBlock(notionapi.BlockTypeParagraph),
+		Paragraph:  notionapi.Paragraph{RichText: createRichText(content)},
+	}
+}
+
+// level is 1-3, as Notion has no smaller headings
+func CreateHeadingBlock(level int, content string) notionapi.Block {
+	heading := notionapi.Heading{RichText: createRichText(content)}
+	switch {
+	case level <= 1:
+		return notionapi.Heading1Block{BasicBlock: basicBlock(notionapi.BlockTypeHeading1), Heading1: heading}
+	case level == 2:
+		return notionapi.Heading2Block{BasicBlock: basicBlock(notionapi.BlockTypeHeading2), Heading2: heading}
+	default:
+		return notionapi.Heading3Block{BasicBlock: basicBlock(notionapi.BlockTypeHeading3), Heading3: heading}
+	}
+}
+
+func CreateBulletedListItemBlock(content string) notionapi.Block {
+	return notionapi.BulletedListItemBlock{
+		BasicBlock:       basicBlock(notionapi.BlockTypeBulletedListItem),
+		BulletedListItem: notionapi.ListItem{RichText: createRichText(content)},
+	}
+}
+
+func CreateNumberedListItemBlock(content string) notionapi.Block {
+	return notionapi.NumberedListItemBlock{
+		BasicBlock:       basicBlock(notionapi.BlockTypeNumberedListItem),
+		NumberedListItem: notionapi.ListItem{RichText: createRichText(content)},
+	}
+}
+
+func CreateToDoBlock(content string, checked bool) notionapi.Block {
+	return notionapi.ToDoBlock{
+		BasicBlock: basicBlock(notionapi.BlockTypeToDo),
+		ToDo:       notionapi.ToDo{RichText: createRichText(content), Checked: checked},
+	}
+}
+
+func CreateCodeBlock(content string, language string) notionapi.Block {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if alias, ok := codeLanguageAliases[language]; ok {
+		language = alias
+	}
+	return notionapi.CodeBlock{
+		BasicBlock: basicBlock(notionapi.BlockTypeCode),
+		Code:       notionapi.Code{RichText: createRichText(content), Language: language},
+	}
+}
+
+func CreateQuoteBlock(content string) notionapi.Block {
+	return notionapi.QuoteBlock{
+		BasicBlock: basicBlock(notionapi.BlockQuote),
+		Quote:      notionapi.Quote{RichText: createRichText(content)},
+	}
+}
+
+const defaultCalloutIcon = "💡"
+
+func CreateCalloutBlock(content string) notionapi.Block {
+	icon := notionapi.Emoji(defaultCalloutIcon)
+	return notionapi.CalloutBlock{
+		BasicBlock: basicBlock(notionapi.BlockCallout),
+		Callout: notionapi.Callout{
+			RichText: createRichText(content),
+			Icon:     &notionapi.Icon{Type: "emoji", Emoji: &icon},
+		},
+	}
+}
+
+func CreateDividerBlock() notionapi.Block {
+	return notionapi.DividerBlock{BasicBlock: basicBlock(notionapi.BlockTypeDivider)}
+}
@@ -0,0 +1,46 @@
+package notion
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/jomei/notionapi"
+)
+
+var (
+	ErrInvalidAPIKey = errors.New("API key is invalid or doesn't have necessary permissions")
+	ErrNotShared     = errors.New("not found or not shared with the integration, add it via \"Add connections\" in Notion")
+)
+
+// maps authentication failures returned by the Notion API to ErrInvalidAPIKey
+// and missing objects to ErrNotShared, other errors are returned unchanged
+func mapAPIError(err error) error {
+	var apiErr *notionapi.Error
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+
+	switch apiErr.Status {
+	case http.StatusUnauthorized:
+		return fmt.Errorf("%w, please run `notidb init` to set a new one", ErrInvalidAPIKey)
+	case http.StatusNotFound:
+		return ErrNotShared
+	}
+	return err
+}
+
+// maps the error like mapAPIError, a missing object is named by its kind and ID
+func notFoundError(kind, id string, err error) error {
+	if err = mapAPIError(err); errors.Is(err, ErrNotShared) {
+		return fmt.Errorf("%s %s %w", kind, id, err)
+	}
+	return err
+}
+
+// returns whether Notion rejected the request as invalid, e.g. because a property
+// doesn't match the database schema
+func IsValidationError(err error) bool {
+	var apiErr *notionapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == "validation_error"
+}
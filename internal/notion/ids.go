@@ -0,0 +1,36 @@
+package notion
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var idPattern = regexp.MustCompile(`[0-9a-fA-F]{32}`)
+
+// extracts a Notion object ID from a raw ID (with or without dashes) or a Notion URL,
+// the returned ID is always in the dashed UUID format
+func ParseID(ref string) (string, bool) {
+	ref = strings.TrimSpace(ref)
+
+	if u, err := url.Parse(ref); err == nil && u.Host != "" {
+		// the ID is the last 32 hex chars of the path, e.g. /workspace/Tasks-<id>
+		matches := idPattern.FindAllString(u.Path, -1)
+		if len(matches) == 0 {
+			return "", false
+		}
+		return formatID(matches[len(matches)-1]), true
+	}
+
+	compact := strings.ReplaceAll(ref, "-", "")
+	if len(compact) != 32 || !idPattern.MatchString(compact) {
+		return "", false
+	}
+	return formatID(compact), true
+}
+
+func formatID(id string) string {
+	id = strings.ToLower(id)
+	return fmt.Sprintf("%s-%s-%s-%s-%s", id[0:8], id[8:12], id[12:16], id[16:20], id[20:32])
+}
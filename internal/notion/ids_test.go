@@ -0,0 +1,38 @@
+package notion
+
+import "testing"
+
+func TestParseID(t *testing.T) {
+	const id = "0123456789abcdef0123456789abcdef"
+	const dashed = "01234567-89ab-cdef-0123-456789abcdef"
+
+	tests := []struct {
+		name   string
+		ref    string
+		want   string
+		wantOk bool
+	}{
+		{name: "compact ID", ref: id, want: dashed, wantOk: true},
+		{name: "dashed ID", ref: dashed, want: dashed, wantOk: true},
+		{name: "uppercase ID", ref: "0123456789ABCDEF0123456789ABCDEF", want: dashed, wantOk: true},
+		{name: "surrounding spaces", ref: "  " + id + "\n", want: dashed, wantOk: true},
+		{name: "URL", ref: "https://www.notion.so/" + id, want: dashed, wantOk: true},
+		{name: "URL with a title", ref: "https://www.notion.so/workspace/Tasks-" + id, want: dashed, wantOk: true},
+		{name: "URL with a view", ref: "https://www.notion.so/workspace/" + id + "?v=fedcba9876543210fedcba9876543210", want: dashed, wantOk: true},
+		{name: "URL without an ID", ref: "https://www.notion.so/workspace/Tasks", wantOk: false},
+		{name: "short ID", ref: id[:31], wantOk: false},
+		{name: "long ID", ref: id + "0", wantOk: false},
+		{name: "not hex", ref: "0123456789abcdef0123456789abcdeg", wantOk: false},
+		{name: "title", ref: "Tasks", wantOk: false},
+		{name: "empty", ref: "", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseID(tt.ref)
+			if ok != tt.wantOk || got != tt.want {
+				t.Errorf("ParseID(%q) = %q, %v, want %q, %v", tt.ref, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package notion
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/jomei/notionapi"
+)
+
+var (
+	headingPattern  = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	toDoPattern     = regexp.MustCompile(`^[-*+]\s+\[([ xX])\]\s*(.*)$`)
+	bulletPattern   = regexp.MustCompile(`^[-*+]\s+(.*)$`)
+	numberedPattern = regexp.MustCompile(`^\d+[.)]\s+(.*)$`)
+	quotePattern    = regexp.MustCompile(`^>\s?(.*)$`)
+	// GitHub alert syntax, e.g. "> [!NOTE] text"
+	calloutPattern = regexp.MustCompile(`^\[!\w+\]\s*(.*)$`)
+	dividerPattern = regexp.MustCompile(`^(-{3,}|\*{3,}|_{3,})$`)
+	fencePattern   = regexp.MustCompile("^(`{3,})\\s*(.*)$")
+	backtickRuns   = regexp.MustCompile("`+")
+)
+
+// converts Markdown into Notion blocks, supporting headings, bulleted, numbered
+// and to-do lists, quotes, callouts, code fences and dividers, inline formatting is kept as text,
+// consecutive lines form a single paragraph or quote and blank lines are skipped
+func MarkdownToBlocks(markdown string) []notionapi.Block {
+	blocks := make([]notionapi.Block, 0)
+	var paragraph []string
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			blocks = append(blocks, CreateParagraphBlock(strings.Join(paragraph, "\n")))
+			paragraph = nil
+		}
+	}
+
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		trimmed := strings.TrimSpace(line)
+
+		if m := fencePattern.FindStringSubmatch(trimmed); m != nil {
+			flushParagraph()
+			var code []string
+			for i++; i < len(lines) && !isClosingFence(lines[i], m[1]); i++ {
+				code = append(code, lines[i])
+			}
+			blocks = append(blocks, CreateCodeBlock(strings.Join(code, "\n"), m[2]))
+			continue
+		}
+
+		if trimmed == "" {
+			flushParagraph()
+			continue
+		}
+
+		if m := quotePattern.FindStringSubmatch(trimmed); m != nil {
+			flushParagraph()
+			callout := calloutPattern.FindStringSubmatch(m[1])
+			quote := []string{m[1]}
+			if callout != nil {
+				quote[0] = callout[1]
+			}
+			// following quote lines continue the quote, unless they start a callout
+			for i+1 < len(lines) {
+				next := quotePattern.FindStringSubmatch(strings.TrimSpace(lines[i+1]))
+				if next == nil || calloutPattern.MatchString(next[1]) {
+					break
+				}
+				quote = append(quote, next[1])
+				i++
+			}
+
+			if callout != nil {
+				blocks = append(blocks, CreateCalloutBlock(strings.Join(quote, "\n")))
+			} else {
+				blocks = append(blocks, CreateQuoteBlock(strings.Join(quote, "\n")))
+			}
+			continue
+		}
+
+		var block notionapi.Block
+		if m := headingPattern.FindStringSubmatch(trimmed); m != nil {
+			block = CreateHeadingBlock(len(m[1]), m[2])
+		} else if dividerPattern.MatchString(trimmed) {
+			block = CreateDividerBlock()
+		} else if m := toDoPattern.FindStringSubmatch(trimmed); m != nil {
+			block = CreateToDoBlock(m[2], m[1] != " ")
+		} else if m := bulletPattern.FindStringSubmatch(trimmed); m != nil {
+			block = CreateBulletedListItemBlock(m[1])
+		} else if m := numberedPattern.FindStringSubmatch(trimmed); m != nil {
+			block = CreateNumberedListItemBlock(m[1])
+		}
+
+		if block == nil {
+			paragraph = append(paragraph, line)
+			continue
+		}
+		flushParagraph()
+		blocks = append(blocks, block)
+	}
+	flushParagraph()
+
+	return blocks
+}
+
+// the closing fence is a line of backticks at least as long as the opening one
+func isClosingFence(line, fence string) bool {
+	trimmed := strings.TrimSpace(line)
+	return len(trimmed) >= len(fence) && strings.Trim(trimmed, "`") == ""
+}
+
+// returns a code fence one backtick longer than the longest run of backticks
+// in the content, so the content can't close it
+func CodeFence(content string) string {
+	length := 3
+	for _, run := range backtickRuns.FindAllString(content, -1) {
+		if len(run) >= length {
+			length = len(run) + 1
+		}
+	}
+	return strings.Repeat("`", length)
+}
@@ -0,0 +1,107 @@
+package notion
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jomei/notionapi"
+)
+
+func TestMarkdownToBlocks(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     []notionapi.Block
+	}{
+		{
+			name:     "empty",
+			markdown: "",
+			want:     []notionapi.Block{},
+		},
+		{
+			name:     "paragraphs",
+			markdown: "first line\nsecond line\n\nnext paragraph",
+			want: []notionapi.Block{
+				CreateParagraphBlock("first line\nsecond line"),
+				CreateParagraphBlock("next paragraph"),
+			},
+		},
+		{
+			name:     "headings",
+			markdown: "# One\n## Two\n### Three",
+			want: []notionapi.Block{
+				CreateHeadingBlock(1, "One"),
+				CreateHeadingBlock(2, "Two"),
+				CreateHeadingBlock(3, "Three"),
+			},
+		},
+		{
+			name:     "lists",
+			markdown: "- [ ] todo\n- [x] done\n- bullet\n1. numbered",
+			want: []notionapi.Block{
+				CreateToDoBlock("todo", false),
+				CreateToDoBlock("done", true),
+				CreateBulletedListItemBlock("bullet"),
+				CreateNumberedListItemBlock("numbered"),
+			},
+		},
+		{
+			name:     "quote and callout",
+			markdown: "> quoted\n> lines\n> [!NOTE] callout",
+			want: []notionapi.Block{
+				CreateQuoteBlock("quoted\nlines"),
+				CreateCalloutBlock("callout"),
+			},
+		},
+		{
+			name:     "divider",
+			markdown: "above\n\n---\n\nbelow",
+			want: []notionapi.Block{
+				CreateParagraphBlock("above"),
+				CreateDividerBlock(),
+				CreateParagraphBlock("below"),
+			},
+		},
+		{
+			name:     "code",
+			markdown: "```go\nfunc main() {}\n\n# not a heading\n```",
+			want: []notionapi.Block{
+				CreateCodeBlock("func main() {}\n\n# not a heading", "go"),
+			},
+		},
+		{
+			name:     "longer fence keeps shorter ones",
+			markdown: "````\n```\nnested\n```\n````\nafter",
+			want: []notionapi.Block{
+				CreateCodeBlock("```\nnested\n```", ""),
+				CreateParagraphBlock("after"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MarkdownToBlocks(tt.markdown); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MarkdownToBlocks(%q) = %#v, want %#v", tt.markdown, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodeFence(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"plain", "```"},
+		{"inline `code`", "```"},
+		{"```\nfenced\n```", "````"},
+		{"`````", "``````"},
+	}
+
+	for _, tt := range tests {
+		if got := CodeFence(tt.content); got != tt.want {
+			t.Errorf("CodeFence(%q) = %q, want %q", tt.content, got, tt.want)
+		}
+	}
+}
@@ -3,37 +3,61 @@ package notion
 import (
 	"context"
 	"fmt"
-	"log"
 	"net/http"
+	"strings"
 
 	"github.com/jomei/notionapi"
 )
 
 var NotionClient *notionapi.Client
 
+// returns all databases shared with the integration, following the pages of the search
 func GetAllNotionDbs() ([]notionapi.Database, error) {
-	res, err := NotionClient.Search.Do(context.Background(), &notionapi.SearchRequest{
-		Filter: notionapi.SearchFilter{
-			Value:    "database",
-			Property: "object",
-		},
-	})
+	return SearchDatabases("").All()
+}
+
+// returns the database whose title matches exactly, the title must be unique
+func FindDatabaseByTitle(title string) (notionapi.Database, error) {
+	dbs, err := SearchDatabases(title).All()
 	if err != nil {
-		return nil, err
+		return notionapi.Database{}, err
 	}
 
-	databases := make([]notionapi.Database, len(res.Results))
-	for i, obj := range res.Results {
-		if db, ok := obj.(*notionapi.Database); ok {
-			databases[i] = *db
+	var matches []notionapi.Database
+	for _, db := range dbs {
+		if DatabaseTitle(db) == title {
+			matches = append(matches, db)
 		}
 	}
 
-	return databases, nil
+	switch len(matches) {
+	case 0:
+		return notionapi.Database{}, fmt.Errorf("no database titled %q found in your workspace or the access is not granted", title)
+	case 1:
+		return matches[0], nil
+	default:
+		return notionapi.Database{}, fmt.Errorf("%d databases are titled %q, use an alias, ID or URL instead", len(matches), title)
+	}
 }
 
-func GetDatabaseSchema(dbId string) (notionapi.PropertyConfigs, error) {
+func DatabaseTitle(db notionapi.Database) string {
+	var title strings.Builder
+	for _, rt := range db.Title {
+		title.WriteString(rt.PlainText)
+	}
+	return title.String()
+}
+
+func GetDatabase(dbId string) (notionapi.Database, error) {
 	db, err := NotionClient.Database.Get(context.Background(), notionapi.DatabaseID(dbId))
+	if err != nil {
+		return notionapi.Database{}, notFoundError("database", dbId, err)
+	}
+	return *db, nil
+}
+
+func GetDatabaseSchema(dbId string) (notionapi.PropertyConfigs, error) {
+	db, err := GetDatabase(dbId)
 	if err != nil {
 		return nil, err
 	}
@@ -45,30 +69,55 @@ type DatabaseEntry struct {
 	Blocks []notionapi.Block
 }
 
+// entries within the API limits are created by a single request, larger ones
+// get their content appended after the page is created
 func AddDatabaseEntry(dbId string, entry DatabaseEntry) (notionapi.Page, error) {
+	trees, err := toBlockTrees(entry.Blocks)
+	if err != nil {
+		return notionapi.Page{}, err
+	}
+	children := entry.Blocks
+	fits := fitsInRequest(trees, 0)
+	if !fits {
+		children = nil
+	}
+
 	page, err := NotionClient.Page.Create(context.Background(), &notionapi.PageCreateRequest{
 		Parent: notionapi.Parent{
 			Type:       "database_id",
 			DatabaseID: notionapi.DatabaseID(dbId),
 		},
 		Properties: entry.Props,
-		Children:   entry.Blocks,
+		Children:   children,
 	})
 	if err != nil {
-		return notionapi.Page{}, err
+		return notionapi.Page{}, notFoundError("database", dbId, err)
+	}
+
+	if !fits {
+		if err := appendBlockTrees(notionapi.BlockID(page.ID), trees); err != nil {
+			return notionapi.Page{}, fmt.Errorf("the entry was created at %s, but not all of its content was added: %w", page.URL, err)
+		}
 	}
 	return *page, nil
 }
 
-func CreateNotionClient(apiKey string) {
-	err := validateNotionAPIKey(apiKey)
+// returns the bot user the API key belongs to, including its workspace
+func GetBotUser() (notionapi.User, error) {
+	user, err := NotionClient.User.Me(context.Background())
 	if err != nil {
-		log.Fatalf("error validating API key: %v \n", err)
+		return notionapi.User{}, mapAPIError(err)
 	}
+	return *user, nil
+}
+
+// creates the client without contacting the API, an invalid key surfaces
+// as ErrInvalidAPIKey on the first real call
+func CreateNotionClient(apiKey string) {
 	NotionClient = notionapi.NewClient(notionapi.Token(apiKey))
 }
 
-func validateNotionAPIKey(apiKey string) error {
+func ValidateNotionAPIKey(apiKey string) error {
 	url := "https://api.notion.com/v1/users/me"
 
 	client := &http.Client{}
@@ -87,7 +136,7 @@ func validateNotionAPIKey(apiKey string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("API key is invalid or doesn't have necessary permissions")
+		return ErrInvalidAPIKey
 	}
 
 	return nil
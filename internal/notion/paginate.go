@@ -0,0 +1,133 @@
+package notion
+
+import (
+	"context"
+
+	"github.com/jomei/notionapi"
+)
+
+// fetches the page of results starting at the cursor, the first page has an empty cursor
+type PageFetcher[T any] func(cursor notionapi.Cursor) (results []T, next notionapi.Cursor, hasMore bool, err error)
+
+// Paginator follows the cursors of a paginated endpoint, one page per call of Next
+type Paginator[T any] struct {
+	fetch  PageFetcher[T]
+	cursor notionapi.Cursor
+	done   bool
+}
+
+func NewPaginator[T any](fetch PageFetcher[T]) *Paginator[T] {
+	return &Paginator[T]{fetch: fetch}
+}
+
+// reports whether there are pages left, it's true before the first page is loaded
+func (p *Paginator[T]) HasNext() bool {
+	return !p.done
+}
+
+// loads the next page, the same page is retried after an error
+func (p *Paginator[T]) Next() ([]T, error) {
+	if p.done {
+		return nil, nil
+	}
+
+	results, next, hasMore, err := p.fetch(p.cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	// a missing cursor would load the first page again
+	p.done = !hasMore || next == ""
+	p.cursor = next
+	return results, nil
+}
+
+// loads all pages left
+func (p *Paginator[T]) All() ([]T, error) {
+	var all []T
+	for p.HasNext() {
+		results, err := p.Next()
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, results...)
+	}
+	return all, nil
+}
+
+// returns the paginator of the search, the objects are pages and databases
+func Search(req notionapi.SearchRequest) *Paginator[notionapi.Object] {
+	return NewPaginator(fetchSearch(req))
+}
+
+// returns the paginator of the databases whose title contains the query, all of them when it's empty
+func SearchDatabases(query string) *Paginator[notionapi.Database] {
+	return searchDatabases(notionapi.SearchRequest{Query: query})
+}
+
+// returns the first page of the most recently edited databases, one request telling
+// which cached schemas are outdated
+func RecentDatabases() ([]notionapi.Database, error) {
+	return searchDatabases(notionapi.SearchRequest{
+		Sort: &notionapi.SortObject{
+			Timestamp: notionapi.TimestampLastEdited,
+			Direction: notionapi.SortOrderDESC,
+		},
+	}).Next()
+}
+
+func searchDatabases(req notionapi.SearchRequest) *Paginator[notionapi.Database] {
+	req.Filter = notionapi.SearchFilter{
+		Value:    "database",
+		Property: "object",
+	}
+	fetch := fetchSearch(req)
+	return NewPaginator(func(cursor notionapi.Cursor) ([]notionapi.Database, notionapi.Cursor, bool, error) {
+		objects, next, hasMore, err := fetch(cursor)
+		if err != nil {
+			return nil, "", false, err
+		}
+
+		var dbs []notionapi.Database
+		for _, obj := range objects {
+			if db, ok := obj.(*notionapi.Database); ok {
+				dbs = append(dbs, *db)
+			}
+		}
+		return dbs, next, hasMore, nil
+	})
+}
+
+func fetchSearch(req notionapi.SearchRequest) PageFetcher[notionapi.Object] {
+	return func(cursor notionapi.Cursor) ([]notionapi.Object, notionapi.Cursor, bool, error) {
+		req.StartCursor = cursor
+		res, err := NotionClient.Search.Do(context.Background(), &req)
+		if err != nil {
+			return nil, "", false, mapAPIError(err)
+		}
+		return res.Results, res.NextCursor, res.HasMore, nil
+	}
+}
+
+// returns the paginator of the pages of the database matching the query
+func QueryDatabase(dbId string, req notionapi.DatabaseQueryRequest) *Paginator[notionapi.Page] {
+	return NewPaginator(func(cursor notionapi.Cursor) ([]notionapi.Page, notionapi.Cursor, bool, error) {
+		req.StartCursor = cursor
+		res, err := NotionClient.Database.Query(context.Background(), notionapi.DatabaseID(dbId), &req)
+		if err != nil {
+			return nil, "", false, notFoundError("database", dbId, err)
+		}
+		return res.Results, res.NextCursor, res.HasMore, nil
+	})
+}
+
+// returns the paginator of the child blocks of the block or page
+func BlockChildren(blockId notionapi.BlockID) *Paginator[notionapi.Block] {
+	return NewPaginator(func(cursor notionapi.Cursor) ([]notionapi.Block, notionapi.Cursor, bool, error) {
+		res, err := NotionClient.Block.GetChildren(context.Background(), blockId, &notionapi.Pagination{StartCursor: cursor})
+		if err != nil {
+			return nil, "", false, notFoundError("block", string(blockId), err)
+		}
+		return res.Results, notionapi.Cursor(res.NextCursor), res.HasMore, nil
+	})
+}
@@ -0,0 +1,152 @@
+package notion
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jomei/notionapi"
+)
+
+// response of the fake endpoint to a cursor
+type fakePage struct {
+	results []int
+	next    notionapi.Cursor
+	hasMore bool
+	err     error
+}
+
+// returns the fetcher answering with the pages in order, the cursors it was called with are recorded
+func fakeFetcher(pages []fakePage, cursors *[]notionapi.Cursor) PageFetcher[int] {
+	calls := 0
+	return func(cursor notionapi.Cursor) ([]int, notionapi.Cursor, bool, error) {
+		*cursors = append(*cursors, cursor)
+		page := pages[calls]
+		calls++
+		return page.results, page.next, page.hasMore, page.err
+	}
+}
+
+func TestPaginator(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name    string
+		pages   []fakePage
+		want    [][]int
+		errs    []error
+		cursors []notionapi.Cursor
+	}{
+		{
+			name:    "single page",
+			pages:   []fakePage{{results: []int{1, 2}}},
+			want:    [][]int{{1, 2}},
+			errs:    []error{nil},
+			cursors: []notionapi.Cursor{""},
+		},
+		{
+			name: "follows the cursors",
+			pages: []fakePage{
+				{results: []int{1}, next: "a", hasMore: true},
+				{results: []int{2}, next: "b", hasMore: true},
+				{results: []int{3}},
+			},
+			want:    [][]int{{1}, {2}, {3}},
+			errs:    []error{nil, nil, nil},
+			cursors: []notionapi.Cursor{"", "a", "b"},
+		},
+		{
+			name: "stops at an empty next cursor",
+			pages: []fakePage{
+				{results: []int{1}, next: "a", hasMore: true},
+				{results: []int{2}, next: "", hasMore: true},
+			},
+			want:    [][]int{{1}, {2}},
+			errs:    []error{nil, nil},
+			cursors: []notionapi.Cursor{"", "a"},
+		},
+		{
+			name: "retries the page after an error",
+			pages: []fakePage{
+				{results: []int{1}, next: "a", hasMore: true},
+				{err: errFailed},
+				{results: []int{2}},
+			},
+			want:    [][]int{{1}, nil, {2}},
+			errs:    []error{nil, errFailed, nil},
+			cursors: []notionapi.Cursor{"", "a", "a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cursors []notionapi.Cursor
+			p := NewPaginator(fakeFetcher(tt.pages, &cursors))
+
+			for i := range tt.want {
+				if !p.HasNext() {
+					t.Fatalf("call %d: HasNext() = false, want true", i)
+				}
+				got, err := p.Next()
+				if !errors.Is(err, tt.errs[i]) {
+					t.Fatalf("call %d: Next() error = %v, want %v", i, err, tt.errs[i])
+				}
+				if !reflect.DeepEqual(got, tt.want[i]) {
+					t.Errorf("call %d: Next() = %v, want %v", i, got, tt.want[i])
+				}
+			}
+			if p.HasNext() {
+				t.Errorf("HasNext() = true after the last page")
+			}
+			if !reflect.DeepEqual(cursors, tt.cursors) {
+				t.Errorf("cursors = %v, want %v", cursors, tt.cursors)
+			}
+		})
+	}
+}
+
+func TestPaginatorAll(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name    string
+		pages   []fakePage
+		want    []int
+		wantErr error
+	}{
+		{
+			name: "all pages",
+			pages: []fakePage{
+				{results: []int{1, 2}, next: "a", hasMore: true},
+				{results: []int{3}},
+			},
+			want: []int{1, 2, 3},
+		},
+		{
+			name:  "empty",
+			pages: []fakePage{{}},
+			want:  nil,
+		},
+		{
+			name: "error",
+			pages: []fakePage{
+				{results: []int{1}, next: "a", hasMore: true},
+				{err: errFailed},
+			},
+			wantErr: errFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cursors []notionapi.Cursor
+			got, err := NewPaginator(fakeFetcher(tt.pages, &cursors)).All()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("All() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("All() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
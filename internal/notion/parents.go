@@ -0,0 +1,147 @@
+package notion
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jomei/notionapi"
+)
+
+const (
+	// max number of titles in the path of a database
+	maxPathDepth = 3
+	// max number of parents followed, blocks like columns have no title
+	maxPathParents = 6
+)
+
+// page, block or database above a database
+type parentNode struct {
+	title  string
+	parent notionapi.Parent
+}
+
+// PathResolver returns the paths of the databases, the loaded parents are kept
+// so the databases can be resolved page by page
+type PathResolver struct {
+	// titles of the databases resolved so far
+	titles map[string]string
+	// nil for parents which aren't shared with the integration, so they are loaded once,
+	// parents failing otherwise, e.g. when rate limited after the retries of the client,
+	// are loaded again by the next databases
+	nodes map[string]*parentNode
+}
+
+func NewPathResolver() *PathResolver {
+	return &PathResolver{titles: make(map[string]string), nodes: make(map[string]*parentNode)}
+}
+
+// returns the paths of the databases by ID, like "Work / Projects", made of the titles
+// of the pages above them, the path ends at pages not shared with the integration
+func ParentPaths(dbs []notionapi.Database) map[string]string {
+	return NewPathResolver().Paths(dbs)
+}
+
+// returns the paths of the databases by ID, see ParentPaths, the databases
+// with a parent which failed to load are left out
+func (r *PathResolver) Paths(dbs []notionapi.Database) map[string]string {
+	for _, db := range dbs {
+		r.titles[normalizeId(string(db.ID))] = DatabaseTitle(db)
+	}
+
+	paths := make(map[string]string)
+	for _, db := range dbs {
+		var path []string
+		parent := db.Parent
+		failed := false
+		for i := 0; i < maxPathParents && len(path) < maxPathDepth; i++ {
+			node, err := r.loadParent(parent)
+			if err != nil {
+				failed = true
+			}
+			if node == nil {
+				break
+			}
+			if node.title != "" {
+				path = append([]string{node.title}, path...)
+			}
+			parent = node.parent
+		}
+		if failed {
+			continue
+		}
+		if len(path) == maxPathDepth && parent.Type != notionapi.ParentTypeWorkspace {
+			path = append([]string{"…"}, path...)
+		}
+		paths[string(db.ID)] = strings.Join(path, " / ")
+	}
+	return paths
+}
+
+// returns the parent, nil when there is none or it isn't shared with the integration,
+// other errors are returned and not kept
+func (r *PathResolver) loadParent(parent notionapi.Parent) (*parentNode, error) {
+	id := parentId(parent)
+	if id == "" {
+		return nil, nil
+	}
+
+	if node, ok := r.nodes[id]; ok {
+		return node, nil
+	}
+
+	var node *parentNode
+	var err error
+	switch parent.Type {
+	case notionapi.ParentTypePageID:
+		var page notionapi.Page
+		if page, err = GetPage(id); err == nil {
+			node = &parentNode{title: PageTitle(page), parent: page.Parent}
+		}
+	case notionapi.ParentTypeBlockID:
+		var block notionapi.Block
+		if block, err = NotionClient.Block.Get(context.Background(), notionapi.BlockID(id)); err == nil && block.GetParent() != nil {
+			node = &parentNode{parent: *block.GetParent()}
+		}
+		err = mapAPIError(err)
+	case notionapi.ParentTypeDatabaseID:
+		if title, ok := r.titles[id]; ok {
+			// the parent database is in the list already, its parents are resolved on their own
+			node = &parentNode{title: title}
+		} else if db, dbErr := GetDatabase(id); dbErr == nil {
+			node = &parentNode{title: DatabaseTitle(db), parent: db.Parent}
+		} else {
+			err = dbErr
+		}
+	}
+
+	if err != nil && !errors.Is(err, ErrNotShared) {
+		return nil, err
+	}
+	r.nodes[id] = node
+	return node, nil
+}
+
+// returns the ID of the page, block or database, empty for the workspace
+func parentId(parent notionapi.Parent) string {
+	var id string
+	switch parent.Type {
+	case notionapi.ParentTypePageID:
+		id = string(parent.PageID)
+	case notionapi.ParentTypeBlockID:
+		id = string(parent.BlockID)
+	case notionapi.ParentTypeDatabaseID:
+		id = string(parent.DatabaseID)
+	default:
+		return ""
+	}
+	return normalizeId(id)
+}
+
+// IDs are returned with and without dashes
+func normalizeId(id string) string {
+	if parsed, ok := ParseID(id); ok {
+		return parsed
+	}
+	return id
+}
@@ -1,6 +1,8 @@
 package notion
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/ChmaraX/notidb/internal/utils"
@@ -18,26 +20,52 @@ func GetSupportedPropTypes() []notionapi.PropertyType {
 		notionapi.PropertyTypeCheckbox,
 		notionapi.PropertyTypeEmail,
 		notionapi.PropertyTypePhoneNumber,
+		notionapi.PropertyTypeURL,
 	}
 }
 
-func CreateContentBlock(content string) notionapi.Block {
-	return notionapi.ParagraphBlock{
-		BasicBlock: notionapi.BasicBlock{
-			Object: "block",
-			Type:   "paragraph",
-		},
-		Paragraph: notionapi.Paragraph{
-			RichText: []notionapi.RichText{
-				{
-					Type: "text",
-					Text: &notionapi.Text{
-						Content: content,
-					},
-				},
-			},
-		},
+// creates a property of the given type from its text representation,
+// as entered in the form or in the config
+func CreateProperty(propType notionapi.PropertyType, value string) (notionapi.Property, error) {
+	if err := ValidateFormat(propType, value); err != nil {
+		return nil, err
 	}
+
+	switch propType {
+	case notionapi.PropertyTypeTitle:
+		return CreateTitleProperty(value), nil
+	case notionapi.PropertyTypeRichText:
+		return CreateRichTextProperty(value), nil
+	case notionapi.PropertyTypeSelect:
+		return CreateSelectProperty(value), nil
+	case notionapi.PropertyTypeMultiSelect:
+		return CreateMultiSelectProperty(SplitOptions(value)), nil
+	case notionapi.PropertyTypeDate:
+		date, err := CreateDateProperty(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse date: %w", err)
+		}
+		return date, nil
+	case notionapi.PropertyTypeCheckbox:
+		v, err := utils.ParseBool(value)
+		if err != nil {
+			return nil, err
+		}
+		return CreateCheckboxProperty(v), nil
+	case notionapi.PropertyTypeNumber:
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("must be number")
+		}
+		return CreateNumberProperty(v), nil
+	case notionapi.PropertyTypeEmail:
+		return CreateEmailProperty(value), nil
+	case notionapi.PropertyTypePhoneNumber:
+		return CreatePhoneNumberProperty(value), nil
+	case notionapi.PropertyTypeURL:
+		return CreateURLProperty(value), nil
+	}
+	return nil, fmt.Errorf("unsupported property type: %s", propType)
 }
 
 func CreateTitleProperty(title string) notionapi.TitleProperty {
@@ -107,3 +135,7 @@ func CreateEmailProperty(email string) notionapi.EmailProperty {
 func CreatePhoneNumberProperty(phoneNumber string) notionapi.PhoneNumberProperty {
 	return notionapi.PhoneNumberProperty{PhoneNumber: phoneNumber}
 }
+
+func CreateURLProperty(url string) notionapi.URLProperty {
+	return notionapi.URLProperty{URL: url}
+}
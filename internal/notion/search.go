@@ -0,0 +1,68 @@
+package notion
+
+import (
+	"time"
+
+	"github.com/jomei/notionapi"
+)
+
+// SearchResult is a page or database found by the search
+type SearchResult struct {
+	ID     string               `json:"id"`
+	Object notionapi.ObjectType `json:"object"`
+	Title  string               `json:"title"`
+	URL    string               `json:"url"`
+	// emoji of the icon, empty for other icons
+	Icon       string    `json:"icon,omitempty"`
+	LastEdited time.Time `json:"last_edited_time"`
+}
+
+// returns the paginator of the pages and databases whose title contains the query,
+// limited to the object type and the direct children of the parent unless they are empty
+func SearchWorkspace(query string, objectType notionapi.ObjectType, parent string) *Paginator[SearchResult] {
+	req := notionapi.SearchRequest{Query: query}
+	if objectType != "" {
+		req.Filter = notionapi.SearchFilter{Value: string(objectType), Property: "object"}
+	}
+	if parent != "" {
+		parent = normalizeId(parent)
+	}
+
+	fetch := fetchSearch(req)
+	return NewPaginator(func(cursor notionapi.Cursor) ([]SearchResult, notionapi.Cursor, bool, error) {
+		objects, next, hasMore, err := fetch(cursor)
+		if err != nil {
+			return nil, "", false, err
+		}
+
+		var results []SearchResult
+		for _, obj := range objects {
+			var result SearchResult
+			var objParent notionapi.Parent
+			switch o := obj.(type) {
+			case *notionapi.Page:
+				result = SearchResult{ID: string(o.ID), Title: PageTitle(*o), URL: o.URL, Icon: iconEmoji(o.Icon), LastEdited: o.LastEditedTime}
+				objParent = o.Parent
+			case *notionapi.Database:
+				result = SearchResult{ID: string(o.ID), Title: DatabaseTitle(*o), URL: o.URL, Icon: iconEmoji(o.Icon), LastEdited: o.LastEditedTime}
+				objParent = o.Parent
+			default:
+				continue
+			}
+			// the search API filters only by the object type
+			if parent != "" && parentId(objParent) != parent {
+				continue
+			}
+			result.Object = obj.GetObject()
+			results = append(results, result)
+		}
+		return results, next, hasMore, nil
+	})
+}
+
+func iconEmoji(icon *notionapi.Icon) string {
+	if icon != nil && icon.Emoji != nil {
+		return string(*icon.Emoji)
+	}
+	return ""
+}
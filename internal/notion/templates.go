@@ -0,0 +1,248 @@
+package notion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jomei/notionapi"
+)
+
+// checkbox property marking template pages when the database config doesn't name another one
+const DefaultTemplateProperty = "Template"
+
+// props computed by Notion, they can't be set when creating a page
+var computedPropTypes = map[notionapi.PropertyType]bool{
+	notionapi.PropertyTypeFormula:        true,
+	notionapi.PropertyTypeRollup:         true,
+	notionapi.PropertyTypeCreatedTime:    true,
+	notionapi.PropertyTypeCreatedBy:      true,
+	notionapi.PropertyTypeLastEditedTime: true,
+	notionapi.PropertyTypeLastEditedBy:   true,
+	notionapi.PropertyTypeUniqueID:       true,
+	notionapi.PropertyTypeVerification:   true,
+}
+
+// blocks which can't be created through the API
+var uncopyableBlockTypes = map[notionapi.BlockType]bool{
+	notionapi.BlockTypeChildPage:     true,
+	notionapi.BlockTypeChildDatabase: true,
+	notionapi.BlockTypeLinkPreview:   true,
+	notionapi.BlockTypeTemplate:      true,
+	notionapi.BlockTypeUnsupported:   true,
+}
+
+// props which can be set when creating a page, files uploaded to Notion are left out
+// as they have expiring URLs
+var writablePropTypes = map[notionapi.PropertyType]bool{
+	notionapi.PropertyTypeTitle:       true,
+	notionapi.PropertyTypeRichText:    true,
+	notionapi.PropertyTypeNumber:      true,
+	notionapi.PropertyTypeSelect:      true,
+	notionapi.PropertyTypeMultiSelect: true,
+	notionapi.PropertyTypeStatus:      true,
+	notionapi.PropertyTypeDate:        true,
+	notionapi.PropertyTypePeople:      true,
+	notionapi.PropertyTypeCheckbox:    true,
+	notionapi.PropertyTypeURL:         true,
+	notionapi.PropertyTypeEmail:       true,
+	notionapi.PropertyTypePhoneNumber: true,
+	notionapi.PropertyTypeRelation:    true,
+}
+
+// reports whether the prop is computed by Notion and can't be set
+func IsComputedProperty(propType notionapi.PropertyType) bool {
+	return computedPropTypes[propType]
+}
+
+func GetPage(pageId string) (notionapi.Page, error) {
+	page, err := NotionClient.Page.Get(context.Background(), notionapi.PageID(pageId))
+	if err != nil {
+		return notionapi.Page{}, notFoundError("page", pageId, err)
+	}
+	return *page, nil
+}
+
+func PageTitle(page notionapi.Page) string {
+	var title strings.Builder
+	for _, prop := range page.Properties {
+		if titleProp, ok := prop.(*notionapi.TitleProperty); ok {
+			for _, rt := range titleProp.Title {
+				title.WriteString(rt.PlainText)
+			}
+		}
+	}
+	return title.String()
+}
+
+// returns the template page of the database with the given title, template pages
+// are marked by the checked templateProp checkbox of the schema
+func FindTemplatePage(dbId string, schema notionapi.PropertyConfigs, templateProp, name string) (notionapi.Page, error) {
+	if propConfig, ok := schema[templateProp]; !ok || propConfig.GetType() != notionapi.PropertyConfigTypeCheckbox {
+		return notionapi.Page{}, fmt.Errorf("the database has no %q checkbox marking template pages", templateProp)
+	}
+
+	var names []string
+	pages := QueryDatabase(dbId, notionapi.DatabaseQueryRequest{
+		Filter: notionapi.PropertyFilter{
+			Property: templateProp,
+			Checkbox: &notionapi.CheckboxFilterCondition{Equals: true},
+		},
+	})
+	for pages.HasNext() {
+		results, err := pages.Next()
+		if err != nil {
+			return notionapi.Page{}, err
+		}
+
+		for _, page := range results {
+			title := PageTitle(page)
+			if title == name {
+				return page, nil
+			}
+			names = append(names, title)
+		}
+	}
+
+	if len(names) == 0 {
+		return notionapi.Page{}, fmt.Errorf("no template pages found, check the %q checkbox of the pages to use as templates", templateProp)
+	}
+	return notionapi.Page{}, fmt.Errorf("no template page titled %q, available: %s", name, strings.Join(names, ", "))
+}
+
+// returns the writable props and the content of the page as a new entry,
+// the excluded prop (e.g. the template checkbox) is left out
+func CopyPage(page notionapi.Page, excludeProp string) (DatabaseEntry, error) {
+	entry := DatabaseEntry{Props: make(notionapi.Properties)}
+
+	for name, prop := range page.Properties {
+		if name == excludeProp || !writablePropTypes[prop.GetType()] {
+			continue
+		}
+		entry.Props[name] = prop
+	}
+
+	blocks, err := copyBlockChildren(notionapi.BlockID(page.ID))
+	if err != nil {
+		return DatabaseEntry{}, err
+	}
+	entry.Blocks = blocks
+
+	return entry, nil
+}
+
+// block read from a page, re-encoded without the read-only fields so it can be created again
+type copiedBlock struct {
+	notionapi.BasicBlock
+	content map[string]interface{}
+}
+
+func (b copiedBlock) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"object":       notionapi.ObjectTypeBlock,
+		"type":         b.Type,
+		string(b.Type): b.content,
+	})
+}
+
+func copyBlockChildren(blockId notionapi.BlockID) ([]notionapi.Block, error) {
+	children, err := BlockChildren(blockId).All()
+	if err != nil {
+		return nil, err
+	}
+
+	var blocks []notionapi.Block
+	for _, block := range children {
+		copied, ok, err := copyBlock(block)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			blocks = append(blocks, copied)
+		}
+	}
+
+	return blocks, nil
+}
+
+// returns false for blocks which can't be created, their children are skipped too,
+// children of any depth are copied, AddDatabaseEntry creates the ones the API can't take at once
+func copyBlock(block notionapi.Block) (notionapi.Block, bool, error) {
+	blockType := block.GetType()
+	if uncopyableBlockTypes[blockType] {
+		return nil, false, nil
+	}
+
+	data, err := json.Marshal(block)
+	if err != nil {
+		return nil, false, err
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, false, err
+	}
+
+	content, _ := raw[string(blockType)].(map[string]interface{})
+	if content == nil {
+		content = make(map[string]interface{})
+	}
+	// media uploaded to Notion have expiring URLs, only external ones can be copied
+	if content["type"] == "file" {
+		return nil, false, nil
+	}
+
+	delete(content, "children")
+	if block.GetHasChildren() {
+		children, err := copyBlockChildren(block.GetID())
+		if err != nil {
+			return nil, false, err
+		}
+		if len(children) > 0 {
+			content["children"] = children
+		}
+	}
+
+	return copiedBlock{
+		BasicBlock: notionapi.BasicBlock{Object: notionapi.ObjectTypeBlock, Type: blockType},
+		content:    content,
+	}, true, nil
+}
+
+// fills the entry with props the entry doesn't set and prepends the template content
+func MergeEntry(entry DatabaseEntry, template DatabaseEntry) DatabaseEntry {
+	merged := DatabaseEntry{
+		Props:  make(notionapi.Properties),
+		Blocks: append(append([]notionapi.Block{}, template.Blocks...), entry.Blocks...),
+	}
+
+	hasTitle := false
+	for name, prop := range entry.Props {
+		merged.Props[name] = prop
+		if isTitleProperty(prop) {
+			hasTitle = true
+		}
+	}
+
+	for name, prop := range template.Props {
+		if _, ok := merged.Props[name]; ok {
+			continue
+		}
+		// the entry title can be keyed by "title" instead of the prop name
+		if hasTitle && isTitleProperty(prop) {
+			continue
+		}
+		merged.Props[name] = prop
+	}
+
+	return merged
+}
+
+// props created by this package don't set their type, so the Go type is checked too
+func isTitleProperty(prop notionapi.Property) bool {
+	switch prop.(type) {
+	case notionapi.TitleProperty, *notionapi.TitleProperty:
+		return true
+	}
+	return prop.GetType() == notionapi.PropertyTypeTitle
+}
@@ -0,0 +1,67 @@
+package notion
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ChmaraX/notidb/internal/utils"
+	"github.com/jomei/notionapi"
+)
+
+var (
+	emailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+	// E.164 allows up to 15 digits, separators are accepted as typed
+	phonePattern          = regexp.MustCompile(`^\+?[0-9]{7,15}$`)
+	phoneSeparatorPattern = regexp.MustCompile(`[\s().-]`)
+)
+
+// checks that the text representation of a property value can be converted
+// into a property of the given type, empty values are valid
+func ValidateFormat(propType notionapi.PropertyType, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	switch propType {
+	case notionapi.PropertyTypeNumber:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("must be number")
+		}
+	case notionapi.PropertyTypeCheckbox:
+		if _, err := utils.ParseBool(value); err != nil {
+			return fmt.Errorf("must be y/n")
+		}
+	case notionapi.PropertyTypeDate:
+		if _, err := CreateDateProperty(value); err != nil {
+			return fmt.Errorf("must be date like 31/12/1990 or 31/12/1990 18:30")
+		}
+	case notionapi.PropertyTypeEmail:
+		if !emailPattern.MatchString(value) {
+			return fmt.Errorf("must be email address")
+		}
+	case notionapi.PropertyTypePhoneNumber:
+		if !phonePattern.MatchString(phoneSeparatorPattern.ReplaceAllString(value, "")) {
+			return fmt.Errorf("must be phone number like +48 123 456 789")
+		}
+	case notionapi.PropertyTypeURL:
+		u, err := url.ParseRequestURI(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("must be URL starting with http:// or https://")
+		}
+	}
+	return nil
+}
+
+// splits the text representation of a multi-select value into option names
+func SplitOptions(value string) []string {
+	var options []string
+	for _, option := range strings.Split(value, ",") {
+		if option = strings.TrimSpace(option); option != "" {
+			options = append(options, option)
+		}
+	}
+	return options
+}
@@ -0,0 +1,44 @@
+package notion
+
+import (
+	"testing"
+
+	"github.com/jomei/notionapi"
+)
+
+func TestValidateFormat(t *testing.T) {
+	tests := []struct {
+		propType notionapi.PropertyType
+		value    string
+		valid    bool
+	}{
+		{notionapi.PropertyTypeNumber, "", true},
+		{notionapi.PropertyTypeNumber, "42", true},
+		{notionapi.PropertyTypeNumber, "-1.5", true},
+		{notionapi.PropertyTypeNumber, "forty", false},
+		{notionapi.PropertyTypeCheckbox, "y", true},
+		{notionapi.PropertyTypeCheckbox, "maybe", false},
+		{notionapi.PropertyTypeDate, "31/12/1990", true},
+		{notionapi.PropertyTypeDate, "31/12/1990 18:30", true},
+		{notionapi.PropertyTypeDate, "tomorrowish", false},
+		{notionapi.PropertyTypeEmail, "jane@example.com", true},
+		{notionapi.PropertyTypeEmail, "jane@example", false},
+		{notionapi.PropertyTypeEmail, "jane doe@example.com", false},
+		{notionapi.PropertyTypePhoneNumber, "+48 123 456 789", true},
+		{notionapi.PropertyTypePhoneNumber, "(555) 123-4567", true},
+		{notionapi.PropertyTypePhoneNumber, "123", false},
+		{notionapi.PropertyTypePhoneNumber, "call me", false},
+		{notionapi.PropertyTypeURL, "https://example.com/path", true},
+		{notionapi.PropertyTypeURL, "http://example.com", true},
+		{notionapi.PropertyTypeURL, "ftp://example.com", false},
+		{notionapi.PropertyTypeURL, "example.com", false},
+		{notionapi.PropertyTypeRichText, "anything", true},
+	}
+
+	for _, tt := range tests {
+		err := ValidateFormat(tt.propType, tt.value)
+		if (err == nil) != tt.valid {
+			t.Errorf("ValidateFormat(%s, %q) error = %v, want valid %v", tt.propType, tt.value, err, tt.valid)
+		}
+	}
+}
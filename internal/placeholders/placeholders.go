@@ -0,0 +1,109 @@
+package placeholders
+
+import (
+	"fmt"
+	"os/exec"
+	"os/user"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+)
+
+// same layout as the date inputs of the form
+const dateLayout = "02/01/2006"
+
+var placeholderPattern = regexp.MustCompile(`{{\s*([\w.]+)\s*}}`)
+
+var resolvers = map[string]func() (string, error){
+	"today": func() (string, error) {
+		return time.Now().Format(dateLayout), nil
+	},
+	"now": func() (string, error) {
+		return time.Now().Format(dateLayout + " 15:04"), nil
+	},
+	"user": func() (string, error) {
+		u, err := user.Current()
+		if err != nil {
+			return "", err
+		}
+		return u.Username, nil
+	},
+	"clipboard": clipboard.ReadAll,
+	"git.branch": func() (string, error) {
+		out, err := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD").Output()
+		if err != nil {
+			return "", fmt.Errorf("not in a git repository")
+		}
+		return strings.TrimSpace(string(out)), nil
+	},
+}
+
+// returns the names of all supported placeholders
+func Names() []string {
+	return []string{"today", "now", "user", "clipboard", "git.branch"}
+}
+
+// replaces placeholders like {{today}} in the text, each placeholder is resolved at most once
+type Expander struct {
+	cache map[string]string
+}
+
+func NewExpander() *Expander {
+	return &Expander{cache: make(map[string]string)}
+}
+
+// returns whether the text contains a placeholder, only such texts need to be expanded
+func Contains(text string) bool {
+	return placeholderPattern.MatchString(text)
+}
+
+// placeholders which can't be resolved are kept as they are, the last failure is returned
+func (e *Expander) Expand(text string) (string, error) {
+	if !Contains(text) {
+		return text, nil
+	}
+
+	var expandErr error
+
+	expanded := placeholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+		name := placeholderPattern.FindStringSubmatch(match)[1]
+
+		if value, ok := e.cache[name]; ok {
+			return value
+		}
+
+		resolve, ok := resolvers[name]
+		if !ok {
+			expandErr = fmt.Errorf("unknown placeholder %s, use one of: %s", match, strings.Join(Names(), ", "))
+			return match
+		}
+
+		value, err := resolve()
+		if err != nil {
+			expandErr = fmt.Errorf("failed to expand %s: %v", match, err)
+			return match
+		}
+
+		e.cache[name] = value
+		return value
+	})
+
+	return expanded, expandErr
+}
+
+// expands placeholders in all values of the map, a value failing to expand doesn't stop
+// the others, the first failure is returned
+func (e *Expander) ExpandAll(values map[string]string) (map[string]string, error) {
+	var expandErr error
+	expanded := make(map[string]string, len(values))
+	for key, value := range values {
+		v, err := e.Expand(value)
+		if err != nil && expandErr == nil {
+			expandErr = fmt.Errorf("%s: %w", key, err)
+		}
+		expanded[key] = v
+	}
+	return expanded, expandErr
+}
@@ -0,0 +1,122 @@
+package placeholders
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// replaces the resolvers for the test, counting how often each one is called
+func fakeResolvers(t *testing.T, values map[string]string, calls map[string]int) {
+	t.Helper()
+	original := resolvers
+	t.Cleanup(func() { resolvers = original })
+
+	resolvers = make(map[string]func() (string, error))
+	for name, value := range values {
+		name, value := name, value
+		resolvers[name] = func() (string, error) {
+			calls[name]++
+			if value == "" {
+				return "", errors.New("not available")
+			}
+			return value, nil
+		}
+	}
+}
+
+func TestExpand(t *testing.T) {
+	values := map[string]string{"today": "01/02/2024", "user": "ada", "git.branch": ""}
+
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		wantErr string
+		// resolvers expected to be called
+		calls map[string]int
+	}{
+		{
+			name:  "no placeholders",
+			text:  "plain text",
+			want:  "plain text",
+			calls: map[string]int{},
+		},
+		{
+			name:  "placeholder",
+			text:  "Notes {{today}}",
+			want:  "Notes 01/02/2024",
+			calls: map[string]int{"today": 1},
+		},
+		{
+			name:  "spaces inside the braces",
+			text:  "{{ user }}",
+			want:  "ada",
+			calls: map[string]int{"user": 1},
+		},
+		{
+			name:  "repeated placeholder is resolved once",
+			text:  "{{today}} and {{today}}",
+			want:  "01/02/2024 and 01/02/2024",
+			calls: map[string]int{"today": 1},
+		},
+		{
+			name:    "unknown placeholder is kept",
+			text:    "{{user}} {{weather}}",
+			want:    "ada {{weather}}",
+			wantErr: "unknown placeholder {{weather}}",
+			calls:   map[string]int{"user": 1},
+		},
+		{
+			name:    "failed placeholder is kept",
+			text:    "{{git.branch}} by {{user}}",
+			want:    "{{git.branch}} by ada",
+			wantErr: "failed to expand {{git.branch}}",
+			calls:   map[string]int{"git.branch": 1, "user": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := make(map[string]int)
+			fakeResolvers(t, values, calls)
+
+			got, err := NewExpander().Expand(tt.text)
+			if got != tt.want {
+				t.Errorf("Expand() = %q, want %q", got, tt.want)
+			}
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("Expand() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Expand() error = %v, want %q", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(calls, tt.calls) {
+				t.Errorf("resolvers called %v, want %v", calls, tt.calls)
+			}
+		})
+	}
+}
+
+func TestExpandAll(t *testing.T) {
+	calls := make(map[string]int)
+	fakeResolvers(t, map[string]string{"today": "01/02/2024", "git.branch": ""}, calls)
+
+	got, err := NewExpander().ExpandAll(map[string]string{
+		"Date":   "{{today}}",
+		"Branch": "{{git.branch}}",
+		"Status": "Open",
+	})
+
+	want := map[string]string{"Date": "01/02/2024", "Branch": "{{git.branch}}", "Status": "Open"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExpandAll() = %v, want %v", got, want)
+	}
+	if err == nil || !strings.HasPrefix(err.Error(), "Branch: ") {
+		t.Errorf("ExpandAll() error = %v, want the failure of Branch", err)
+	}
+	if calls["today"] != 1 {
+		t.Errorf("today resolved %d times, want 1", calls["today"])
+	}
+}
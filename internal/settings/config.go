@@ -0,0 +1,371 @@
+package settings
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+var ErrCorruptConfig = errors.New("config file is corrupt")
+
+const (
+	ConfigVersion  = 1
+	ConfigDirName  = "notidb"
+	ConfigFileName = "config.yaml"
+)
+
+type Config struct {
+	Version       int                       `yaml:"version"`
+	ActiveProfile string                    `yaml:"active_profile"`
+	Profiles      map[string]*ProfileConfig `yaml:"profiles"`
+	// built-in or user-defined theme, detected from the terminal background when empty
+	Theme  string                  `yaml:"theme,omitempty"`
+	Themes map[string]*ThemeConfig `yaml:"themes,omitempty"`
+	Keys   KeysConfig              `yaml:"keys,omitempty"`
+}
+
+// key bindings of the TUI, actions not listed keep the keys of the preset
+type KeysConfig struct {
+	// default, vim or emacs, default when empty
+	Preset string `yaml:"preset,omitempty"`
+	// keys by action, like save: [ctrl+s]
+	Form       map[string][]string `yaml:"form,omitempty"`
+	FormConfig map[string][]string `yaml:"form_config,omitempty"`
+	List       map[string][]string `yaml:"list,omitempty"`
+}
+
+// settings of a single profile
+type ProfileConfig struct {
+	DefaultDatabaseId string                     `yaml:"default_database"`
+	Aliases           map[string]string          `yaml:"aliases,omitempty"`
+	KeyringBackend    string                     `yaml:"keyring_backend,omitempty"`
+	APIKeyCommand     string                     `yaml:"api_key_command,omitempty"`
+	Databases         map[string]*DatabaseConfig `yaml:"databases,omitempty"`
+}
+
+// preferences of a single database, keyed by database ID or alias
+type DatabaseConfig struct {
+	// property values pre-filled in the form and used when not given on the command line
+	Defaults  map[string]string    `yaml:"defaults,omitempty"`
+	Templates map[string]*Template `yaml:"templates,omitempty"`
+	// checkbox property marking template pages in Notion, "Template" when empty
+	TemplateProperty string `yaml:"template_property,omitempty"`
+	// Notion template pages by name, given by page ID or URL
+	NotionTemplates map[string]string `yaml:"notion_templates,omitempty"`
+	Form            FormConfig        `yaml:"form,omitempty"`
+}
+
+// layout of the add form
+type FormConfig struct {
+	// property names in the order of the form, the title and unlisted props
+	// go first and last, unlisted ones in alphabetical order
+	Order  []string                `yaml:"order,omitempty"`
+	Fields map[string]*FieldConfig `yaml:"fields,omitempty"`
+}
+
+// options of a single form field, keyed by property name
+type FieldConfig struct {
+	Hidden      bool   `yaml:"hidden,omitempty"`
+	Required    bool   `yaml:"required,omitempty"`
+	Label       string `yaml:"label,omitempty"`
+	Placeholder string `yaml:"placeholder,omitempty"`
+	// regular expression the whole value must match
+	Pattern string `yaml:"pattern,omitempty"`
+	// bounds of number values
+	Min *float64 `yaml:"min,omitempty"`
+	Max *float64 `yaml:"max,omitempty"`
+	// allowed values of select and multi-select props
+	Options []string `yaml:"options,omitempty"`
+}
+
+func (f FieldConfig) IsZero() bool {
+	return !f.Hidden && !f.Required && f.Label == "" && f.Placeholder == "" &&
+		f.Pattern == "" && f.Min == nil && f.Max == nil && len(f.Options) == 0
+}
+
+// user-defined theme, colors are hex codes like "#FF06B7" or ANSI numbers like "170",
+// unset ones are taken from the base theme
+type ThemeConfig struct {
+	// built-in theme the colors are based on, dark when empty
+	Base     string `yaml:"base,omitempty"`
+	Accent   string `yaml:"accent,omitempty"`
+	Muted    string `yaml:"muted,omitempty"`
+	Error    string `yaml:"error,omitempty"`
+	Success  string `yaml:"success,omitempty"`
+	Warning  string `yaml:"warning,omitempty"`
+	Selected string `yaml:"selected,omitempty"`
+}
+
+// named preset of property values and body Markdown, both can contain placeholders like {{today}}
+type Template struct {
+	Values map[string]string `yaml:"values,omitempty"`
+	Body   string            `yaml:"body,omitempty"`
+}
+
+func newProfileConfig() *ProfileConfig {
+	return &ProfileConfig{DefaultDatabaseId: NoDefaultDatabaseId}
+}
+
+func newConfig() *Config {
+	return &Config{
+		Version:       ConfigVersion,
+		ActiveProfile: DefaultProfile,
+		Profiles: map[string]*ProfileConfig{
+			DefaultProfile: newProfileConfig(),
+		},
+	}
+}
+
+// config location following the XDG base directory spec
+func SettingsFilePath() (string, error) {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		configHome = filepath.Join(homeDir, ".config")
+	}
+	return filepath.Join(configHome, ConfigDirName, ConfigFileName), nil
+}
+
+// directory for data other than the config, like the keyring file
+func AppDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, NotiDBAppDir), nil
+}
+
+// creates the config file, migrating the old settings file when present,
+// and recovers from a corrupt config
+func EnsureSettingsFileExists() error {
+	configFilePath, err := SettingsFilePath()
+	if err != nil {
+		return err
+	}
+
+	// a valid config is only read, the lock is taken when the config is written
+	if !configNeedsSetup(configFilePath) {
+		return nil
+	}
+
+	// create the directory if it doesn't exist
+	if err := os.MkdirAll(filepath.Dir(configFilePath), DirPermMode); err != nil {
+		return err
+	}
+
+	unlock, err := lockFile(configFilePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// check if the config file exists, and create it if not,
+	// another process may have created it while waiting for the lock
+	if _, err := os.Stat(configFilePath); os.IsNotExist(err) {
+		config, legacyFilePath, err := migrateLegacySettingsFile()
+		if err != nil {
+			return fmt.Errorf("failed to migrate %s: %w", SettingsFileName, err)
+		}
+		if err := writeConfig(config, configFilePath); err != nil {
+			return err
+		}
+		// keep the old file as a backup, so it's not migrated again
+		if legacyFilePath != "" {
+			return os.Rename(legacyFilePath, legacyFilePath+".bak")
+		}
+		return nil
+	}
+
+	if _, err := readConfig(configFilePath); errors.Is(err, ErrCorruptConfig) {
+		return recoverConfig(configFilePath, err)
+	}
+
+	return nil
+}
+
+// reports whether the config is missing or corrupt
+func configNeedsSetup(configFilePath string) bool {
+	_, err := readConfig(configFilePath)
+	return errors.Is(err, os.ErrNotExist) || errors.Is(err, ErrCorruptConfig)
+}
+
+// moves the corrupt config aside and restores the backup, or the defaults if the backup is corrupt too
+func recoverConfig(configFilePath string, cause error) error {
+	corruptFilePath := configFilePath + ".corrupt"
+	if err := os.Rename(configFilePath, corruptFilePath); err != nil {
+		return err
+	}
+
+	config, err := readConfig(backupFilePath(configFilePath))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v, it was moved to %s and the default config was created\n", cause, corruptFilePath)
+		config = newConfig()
+	} else {
+		fmt.Fprintf(os.Stderr, "Warning: %v, it was moved to %s and the last backup was restored\n", cause, corruptFilePath)
+	}
+
+	return writeConfig(config, configFilePath)
+}
+
+func backupFilePath(configFilePath string) string {
+	return configFilePath + ".bak"
+}
+
+func loadConfig() (*Config, error) {
+	configFilePath, err := SettingsFilePath()
+	if err != nil {
+		return nil, err
+	}
+	return readConfig(configFilePath)
+}
+
+// runs a read-modify-write of the config under the file lock
+func updateConfig(update func(config *Config) error) error {
+	configFilePath, err := SettingsFilePath()
+	if err != nil {
+		return err
+	}
+
+	unlock, err := lockFile(configFilePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	config, err := readConfig(configFilePath)
+	if err != nil {
+		return err
+	}
+
+	if err := update(config); err != nil {
+		return err
+	}
+
+	// the current config was just read successfully, keep it as the backup for recovery
+	if err := copyFileAtomic(configFilePath, backupFilePath(configFilePath)); err != nil {
+		return err
+	}
+	return writeConfig(config, configFilePath)
+}
+
+func readConfig(filePath string) (*Config, error) {
+	file, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(file, &config); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptConfig, err)
+	}
+	if config.Version > ConfigVersion {
+		return nil, fmt.Errorf("config version %d is newer than supported version %d, please upgrade NotiDB", config.Version, ConfigVersion)
+	}
+
+	normalizeConfig(&config)
+	return &config, nil
+}
+
+func writeConfig(config *Config, filePath string) error {
+	var data bytes.Buffer
+	encoder := yaml.NewEncoder(&data)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(config); err != nil {
+		return err
+	}
+	return WriteFileAtomic(filePath, data.Bytes())
+}
+
+func copyFileAtomic(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(dst, data)
+}
+
+// fills in values missing in hand-edited or older configs
+func normalizeConfig(config *Config) {
+	config.Version = ConfigVersion
+	if config.Profiles == nil {
+		config.Profiles = make(map[string]*ProfileConfig)
+	}
+	if _, ok := config.Profiles[DefaultProfile]; !ok {
+		config.Profiles[DefaultProfile] = newProfileConfig()
+	}
+	for name, profile := range config.Profiles {
+		if profile == nil {
+			config.Profiles[name] = newProfileConfig()
+		} else if profile.DefaultDatabaseId == "" {
+			profile.DefaultDatabaseId = NoDefaultDatabaseId
+		}
+	}
+	if config.ActiveProfile == "" {
+		config.ActiveProfile = DefaultProfile
+	}
+}
+
+// settings.json layouts used before the YAML config, with and without profiles
+type legacySettingsFile struct {
+	ActiveProfile     string `json:"activeProfile"`
+	DefaultDatabaseId string `json:"defaultDatabase"`
+	Profiles          map[string]*struct {
+		DefaultDatabaseId string            `json:"defaultDatabase"`
+		Aliases           map[string]string `json:"aliases"`
+		KeyringBackend    string            `json:"keyringBackend"`
+		APIKeyCommand     string            `json:"apiKeyCommand"`
+	} `json:"profiles"`
+}
+
+// converts ~/.notidb/settings.json into a config, returns an empty path if there is nothing to migrate
+func migrateLegacySettingsFile() (*Config, string, error) {
+	config := newConfig()
+
+	appDir, err := AppDir()
+	if err != nil {
+		return nil, "", err
+	}
+	legacyFilePath := filepath.Join(appDir, SettingsFileName)
+
+	file, err := os.ReadFile(legacyFilePath)
+	if os.IsNotExist(err) {
+		return config, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	var legacy legacySettingsFile
+	if err := json.Unmarshal(file, &legacy); err != nil {
+		return nil, "", err
+	}
+
+	if legacy.DefaultDatabaseId != "" {
+		config.Profiles[DefaultProfile].DefaultDatabaseId = legacy.DefaultDatabaseId
+	}
+	for name, p := range legacy.Profiles {
+		if p == nil {
+			continue
+		}
+		config.Profiles[name] = &ProfileConfig{
+			DefaultDatabaseId: p.DefaultDatabaseId,
+			Aliases:           p.Aliases,
+			KeyringBackend:    p.KeyringBackend,
+			APIKeyCommand:     p.APIKeyCommand,
+		}
+	}
+	if legacy.ActiveProfile != "" {
+		config.ActiveProfile = legacy.ActiveProfile
+	}
+	normalizeConfig(config)
+
+	return config, legacyFilePath, nil
+}
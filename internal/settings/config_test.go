@@ -0,0 +1,226 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// points the config and the app dir to temporary dirs, returns the config file path
+func setupConfig(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv(ProfileEnvVar, "")
+
+	configFilePath, err := SettingsFilePath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return configFilePath
+}
+
+func writeLegacySettings(t *testing.T, content string) string {
+	t.Helper()
+	appDir, err := AppDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(appDir, DirPermMode); err != nil {
+		t.Fatal(err)
+	}
+	legacyFilePath := filepath.Join(appDir, SettingsFileName)
+	if err := os.WriteFile(legacyFilePath, []byte(content), FilePermMode); err != nil {
+		t.Fatal(err)
+	}
+	return legacyFilePath
+}
+
+func TestMigrateLegacySettings(t *testing.T) {
+	tests := []struct {
+		name   string
+		legacy string
+		want   *Config
+		// the legacy file is kept as .bak once migrated
+		wantBackup bool
+		wantErr    bool
+	}{
+		{
+			name: "no legacy settings",
+			want: newConfig(),
+		},
+		{
+			name:   "default database only",
+			legacy: `{"defaultDatabase": "db1"}`,
+			want: &Config{
+				Version:       ConfigVersion,
+				ActiveProfile: DefaultProfile,
+				Profiles: map[string]*ProfileConfig{
+					DefaultProfile: {DefaultDatabaseId: "db1"},
+				},
+			},
+			wantBackup: true,
+		},
+		{
+			name: "profiles",
+			legacy: `{
+				"activeProfile": "work",
+				"profiles": {
+					"work": {"defaultDatabase": "db2", "aliases": {"tasks": "db2"}, "keyringBackend": "file"},
+					"home": {"apiKeyCommand": "pass notion"}
+				}
+			}`,
+			want: &Config{
+				Version:       ConfigVersion,
+				ActiveProfile: "work",
+				Profiles: map[string]*ProfileConfig{
+					DefaultProfile: {DefaultDatabaseId: NoDefaultDatabaseId},
+					"work":         {DefaultDatabaseId: "db2", Aliases: map[string]string{"tasks": "db2"}, KeyringBackend: "file"},
+					"home":         {DefaultDatabaseId: NoDefaultDatabaseId, APIKeyCommand: "pass notion"},
+				},
+			},
+			wantBackup: true,
+		},
+		{
+			name:    "invalid legacy settings",
+			legacy:  `{"defaultDatabase": `,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configFilePath := setupConfig(t)
+			var legacyFilePath string
+			if tt.legacy != "" {
+				legacyFilePath = writeLegacySettings(t, tt.legacy)
+			}
+
+			err := EnsureSettingsFileExists()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EnsureSettingsFileExists() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if _, err := os.Stat(configFilePath); !os.IsNotExist(err) {
+					t.Errorf("config was created from invalid legacy settings")
+				}
+				return
+			}
+
+			got, err := readConfig(configFilePath)
+			if err != nil {
+				t.Fatalf("readConfig() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("migrated config = %+v, want %+v", got, tt.want)
+			}
+
+			if tt.wantBackup {
+				if _, err := os.Stat(legacyFilePath); !os.IsNotExist(err) {
+					t.Errorf("legacy settings were not moved")
+				}
+				if _, err := os.Stat(legacyFilePath + ".bak"); err != nil {
+					t.Errorf("legacy settings were not kept as backup: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestRecoverConfig(t *testing.T) {
+	const corrupt = "profiles: [\n"
+	backup := newConfig()
+	backup.ActiveProfile = "work"
+	backup.Profiles["work"] = newProfileConfig()
+
+	tests := []struct {
+		name   string
+		config string
+		// backup written before the run, none when nil
+		backup *Config
+		// backup file content, written instead of the backup config
+		rawBackup string
+		want      *Config
+		// the config is moved aside as .corrupt
+		wantCorrupt bool
+		// the lock file is only created when the config is written
+		wantLock bool
+	}{
+		{
+			name:   "valid config is left alone",
+			config: "version: 1\nactive_profile: default\n",
+			want:   newConfig(),
+		},
+		{
+			name:        "backup is restored",
+			config:      corrupt,
+			backup:      backup,
+			want:        backup,
+			wantCorrupt: true,
+			wantLock:    true,
+		},
+		{
+			name:        "defaults without a backup",
+			config:      corrupt,
+			want:        newConfig(),
+			wantCorrupt: true,
+			wantLock:    true,
+		},
+		{
+			name:        "defaults with a corrupt backup",
+			config:      corrupt,
+			rawBackup:   corrupt,
+			want:        newConfig(),
+			wantCorrupt: true,
+			wantLock:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configFilePath := setupConfig(t)
+			if err := os.MkdirAll(filepath.Dir(configFilePath), DirPermMode); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(configFilePath, []byte(tt.config), FilePermMode); err != nil {
+				t.Fatal(err)
+			}
+			if tt.backup != nil {
+				if err := writeConfig(tt.backup, backupFilePath(configFilePath)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.rawBackup != "" {
+				if err := os.WriteFile(backupFilePath(configFilePath), []byte(tt.rawBackup), FilePermMode); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := EnsureSettingsFileExists(); err != nil {
+				t.Fatalf("EnsureSettingsFileExists() error = %v", err)
+			}
+
+			got, err := readConfig(configFilePath)
+			if err != nil {
+				t.Fatalf("readConfig() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("recovered config = %+v, want %+v", got, tt.want)
+			}
+
+			corruptContent, err := os.ReadFile(configFilePath + ".corrupt")
+			if tt.wantCorrupt && (err != nil || string(corruptContent) != tt.config) {
+				t.Errorf("corrupt config was not moved aside: %v", err)
+			}
+			if !tt.wantCorrupt && err == nil {
+				t.Errorf("valid config was moved aside")
+			}
+
+			if _, err := os.Stat(configFilePath + ".lock"); (err == nil) != tt.wantLock {
+				t.Errorf("lock file exists = %v, want %v", err == nil, tt.wantLock)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package settings
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+const (
+	lockTimeout    = 5 * time.Second
+	lockRetryDelay = 50 * time.Millisecond
+)
+
+// takes an exclusive lock guarding the file against concurrent notidb processes,
+// the returned function releases it
+func lockFile(filePath string) (func(), error) {
+	lock := flock.New(filePath + ".lock")
+
+	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+	defer cancel()
+
+	locked, err := lock.TryLockContext(ctx, lockRetryDelay)
+	if err != nil || !locked {
+		return nil, fmt.Errorf("failed to lock %s, another notidb process might be running: %v", filePath, err)
+	}
+
+	return func() { lock.Unlock() }, nil
+}
+
+// writes the file via a temp file and rename, so readers never see a partially written file
+func WriteFileAtomic(filePath string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), filepath.Base(filePath)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	// no-op once the file is renamed
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(FilePermMode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filePath)
+}
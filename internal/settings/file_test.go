@@ -0,0 +1,78 @@
+package settings
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLockFile(t *testing.T) {
+	tests := []struct {
+		name string
+		// the lock is held by someone else for this long, not at all when zero
+		held time.Duration
+	}{
+		{name: "free lock"},
+		{name: "lock released while waiting", held: 200 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), ConfigFileName)
+
+			if tt.held > 0 {
+				unlock, err := lockFile(filePath)
+				if err != nil {
+					t.Fatalf("lockFile() error = %v", err)
+				}
+				time.AfterFunc(tt.held, unlock)
+			}
+
+			start := time.Now()
+			unlock, err := lockFile(filePath)
+			if err != nil {
+				t.Fatalf("lockFile() error = %v", err)
+			}
+			unlock()
+			if waited := time.Since(start); waited < tt.held {
+				t.Errorf("lockFile() returned after %v while the lock was held for %v", waited, tt.held)
+			}
+		})
+	}
+}
+
+func TestUpdateConfigConcurrently(t *testing.T) {
+	setupConfig(t)
+	if err := EnsureSettingsFileExists(); err != nil {
+		t.Fatal(err)
+	}
+
+	// each update reads the config first, without the lock some of the profiles would be lost
+	const updates = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, updates)
+	for i := 0; i < updates; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- AddProfile(fmt.Sprintf("profile-%d", i))
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("AddProfile() error = %v", err)
+		}
+	}
+	profiles, err := ListProfiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(profiles) != updates+1 {
+		t.Errorf("ListProfiles() has %d profiles, want %d", len(profiles), updates+1)
+	}
+}
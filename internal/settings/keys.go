@@ -0,0 +1,16 @@
+package settings
+
+func GetKeysConfig() (KeysConfig, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return KeysConfig{}, err
+	}
+	return config.Keys, nil
+}
+
+func SetKeyPreset(name string) error {
+	return updateConfig(func(config *Config) error {
+		config.Keys.Preset = name
+		return nil
+	})
+}
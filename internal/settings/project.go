@@ -0,0 +1,61 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+const ProjectFileName = ".notidb.yaml"
+
+// project-local config, binds a directory tree to a profile and database
+type ProjectConfig struct {
+	Version int    `yaml:"version"`
+	Profile string `yaml:"profile,omitempty"`
+	// alias, ID, URL or title of the database
+	Database string `yaml:"database,omitempty"`
+	// property values for the project database, override the ones from the user config
+	Defaults map[string]string `yaml:"defaults,omitempty"`
+}
+
+// searches for the project file from the current directory upwards,
+// returns an empty path if there is none
+func ProjectFilePath() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		filePath := filepath.Join(dir, ProjectFileName)
+		if _, err := os.Stat(filePath); err == nil {
+			return filePath, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// returns the project config, or an empty one when there is no project file
+func LoadProjectConfig() (*ProjectConfig, error) {
+	filePath, err := ProjectFilePath()
+	if err != nil || filePath == "" {
+		return &ProjectConfig{}, err
+	}
+
+	file, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var project ProjectConfig
+	if err := yaml.Unmarshal(file, &project); err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
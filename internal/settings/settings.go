@@ -1,98 +1,358 @@
 package settings
 
 import (
-	"encoding/json"
+	"fmt"
 	"os"
-	"path/filepath"
+	"regexp"
+	"sort"
 )
 
 const (
 	NoDefaultDatabaseId = "-1"
+	DefaultProfile      = "default"
+	ProfileEnvVar       = "NOTIDB_PROFILE"
 	NotiDBAppDir        = ".notidb"
 	SettingsFileName    = "settings.json"
 	DirPermMode         = 0700
 	FilePermMode        = 0600
 )
 
-type UserSettings struct {
-	DefaultDatabaseId string `json:"defaultDatabase"`
+// profile selected by the --profile flag, takes precedence over the env var
+var profileOverride string
+
+func SetProfileOverride(name string) {
+	profileOverride = name
 }
 
-func GetDefaultDatabase() (string, error) {
-	settingsFilePath, err := getSettingsFilePath()
+// returns the profile used by the current invocation: --profile flag,
+// NOTIDB_PROFILE env var, project file, active profile from the config, "default"
+func CurrentProfile() (string, error) {
+	if profileOverride != "" {
+		return profileOverride, nil
+	}
+	if name := os.Getenv(ProfileEnvVar); name != "" {
+		return name, nil
+	}
+
+	project, err := LoadProjectConfig()
 	if err != nil {
 		return "", err
 	}
+	if project.Profile != "" {
+		return project.Profile, nil
+	}
 
-	settings, err := readSettings(settingsFilePath)
+	config, err := loadConfig()
 	if err != nil {
 		return "", err
 	}
+	return config.ActiveProfile, nil
+}
 
-	return settings.DefaultDatabaseId, nil
+func GetDefaultDatabase() (string, error) {
+	profile, err := getCurrentProfileConfig()
+	if err != nil {
+		return "", err
+	}
+	return profile.DefaultDatabaseId, nil
 }
 
 func SetDefaultDatabase(dbId string) error {
-	settingsFilePath, err := getSettingsFilePath()
+	return updateCurrentProfileConfig(func(profile *ProfileConfig) error {
+		profile.DefaultDatabaseId = dbId
+		return nil
+	})
+}
+
+// returns the database bound by the project file, empty when there is none
+func GetProjectDatabase() (string, error) {
+	project, err := LoadProjectConfig()
 	if err != nil {
-		return err
+		return "", err
 	}
+	return project.Database, nil
+}
 
-	settings, err := readSettings(settingsFilePath)
+// returns preferences of the database, the project defaults are merged in
+// when the database is the one bound by the project file
+func GetDatabaseConfig(dbId string, isProjectDb bool) (DatabaseConfig, error) {
+	profile, err := getCurrentProfileConfig()
 	if err != nil {
-		return err
+		return DatabaseConfig{}, err
+	}
+
+	config := DatabaseConfig{
+		Defaults:        make(map[string]string),
+		Templates:       make(map[string]*Template),
+		NotionTemplates: make(map[string]string),
+	}
+	if _, dbConfig := findDatabaseConfig(profile, dbId); dbConfig != nil {
+		config.TemplateProperty = dbConfig.TemplateProperty
+		config.Form = dbConfig.Form
+		for name, pageRef := range dbConfig.NotionTemplates {
+			config.NotionTemplates[name] = pageRef
+		}
+		for name, value := range dbConfig.Defaults {
+			config.Defaults[name] = value
+		}
+		for name, template := range dbConfig.Templates {
+			config.Templates[name] = template
+		}
+	}
+
+	if isProjectDb {
+		project, err := LoadProjectConfig()
+		if err != nil {
+			return DatabaseConfig{}, err
+		}
+		for name, value := range project.Defaults {
+			config.Defaults[name] = value
+		}
 	}
 
-	settings.DefaultDatabaseId = dbId
-	return writeSettings(settings, settingsFilePath)
+	return config, nil
 }
 
-func EnsureSettingsFileExists() error {
-	settingsFilePath, err := getSettingsFilePath()
+// database preferences can be keyed by the database ID or by any of its aliases,
+// returns the key together with the preferences
+func findDatabaseConfig(profile *ProfileConfig, dbId string) (string, *DatabaseConfig) {
+	if dbConfig, ok := profile.Databases[dbId]; ok && dbConfig != nil {
+		return dbId, dbConfig
+	}
+	for alias, aliasDbId := range profile.Aliases {
+		if aliasDbId != dbId {
+			continue
+		}
+		if dbConfig, ok := profile.Databases[alias]; ok && dbConfig != nil {
+			return alias, dbConfig
+		}
+	}
+	return "", nil
+}
+
+// updates preferences of the database, creating them if there are none yet
+func updateDatabaseConfig(dbId string, update func(dbConfig *DatabaseConfig) error) error {
+	return updateCurrentProfileConfig(func(profile *ProfileConfig) error {
+		_, dbConfig := findDatabaseConfig(profile, dbId)
+		if dbConfig == nil {
+			if profile.Databases == nil {
+				profile.Databases = make(map[string]*DatabaseConfig)
+			}
+			dbConfig = &DatabaseConfig{}
+			profile.Databases[dbId] = dbConfig
+		}
+		return update(dbConfig)
+	})
+}
+
+func SaveTemplate(dbId, name string, template Template) error {
+	return updateDatabaseConfig(dbId, func(dbConfig *DatabaseConfig) error {
+		if dbConfig.Templates == nil {
+			dbConfig.Templates = make(map[string]*Template)
+		}
+		dbConfig.Templates[name] = &template
+		return nil
+	})
+}
+
+func RemoveTemplate(dbId, name string) error {
+	return updateDatabaseConfig(dbId, func(dbConfig *DatabaseConfig) error {
+		if _, ok := dbConfig.Templates[name]; !ok {
+			return fmt.Errorf("template %q does not exist", name)
+		}
+		delete(dbConfig.Templates, name)
+		return nil
+	})
+}
+
+func SaveFormConfig(dbId string, form FormConfig) error {
+	return updateDatabaseConfig(dbId, func(dbConfig *DatabaseConfig) error {
+		dbConfig.Form = form
+		return nil
+	})
+}
+
+func GetKeyringBackend() (string, error) {
+	profile, err := getCurrentProfileConfig()
 	if err != nil {
-		return err
+		return "", err
+	}
+	return profile.KeyringBackend, nil
+}
+
+func SetKeyringBackend(backend string) error {
+	return updateCurrentProfileConfig(func(profile *ProfileConfig) error {
+		profile.KeyringBackend = backend
+		return nil
+	})
+}
+
+// command printing the API key, used instead of the keyring when set
+func GetAPIKeyCommand() (string, error) {
+	profile, err := getCurrentProfileConfig()
+	if err != nil {
+		return "", err
 	}
-	appDir := filepath.Dir(settingsFilePath)
+	return profile.APIKeyCommand, nil
+}
+
+func SetAPIKeyCommand(command string) error {
+	return updateCurrentProfileConfig(func(profile *ProfileConfig) error {
+		profile.APIKeyCommand = command
+		return nil
+	})
+}
+
+// returns the database aliases of the current profile mapped to database IDs
+func GetAliases() (map[string]string, error) {
+	profile, err := getCurrentProfileConfig()
+	if err != nil {
+		return nil, err
+	}
+	if profile.Aliases == nil {
+		return map[string]string{}, nil
+	}
+	return profile.Aliases, nil
+}
 
-	// create the directory if it doesn't exist
-	if err := os.MkdirAll(appDir, DirPermMode); err != nil {
+func SetAlias(alias, dbId string) error {
+	return updateCurrentProfileConfig(func(profile *ProfileConfig) error {
+		if profile.Aliases == nil {
+			profile.Aliases = make(map[string]string)
+		}
+		profile.Aliases[alias] = dbId
+		return nil
+	})
+}
+
+func RemoveAlias(alias string) error {
+	return updateCurrentProfileConfig(func(profile *ProfileConfig) error {
+		if _, ok := profile.Aliases[alias]; !ok {
+			return fmt.Errorf("alias %q does not exist", alias)
+		}
+		delete(profile.Aliases, alias)
+		return nil
+	})
+}
+
+// clears all settings of the current profile, the profile itself is kept
+func ResetProfile() error {
+	return updateCurrentProfileConfig(func(profile *ProfileConfig) error {
+		*profile = *newProfileConfig()
+		return nil
+	})
+}
+
+func ListProfiles() ([]string, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(config.Profiles))
+	for name := range config.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// profile names end up in keyring entries and file names, so they are kept simple
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func ValidateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q, only letters, digits, - and _ are allowed", name)
+	}
+	return nil
+}
+
+func AddProfile(name string) error {
+	if err := ValidateProfileName(name); err != nil {
 		return err
 	}
 
-	// check if the settings file exists, and create it if not
-	if _, err := os.Stat(settingsFilePath); os.IsNotExist(err) {
-		defaultSettings := UserSettings{
-			DefaultDatabaseId: NoDefaultDatabaseId,
+	return updateConfig(func(config *Config) error {
+		if _, ok := config.Profiles[name]; ok {
+			return fmt.Errorf("profile %q already exists", name)
+		}
+
+		config.Profiles[name] = newProfileConfig()
+		return nil
+	})
+}
+
+func UseProfile(name string) error {
+	return updateConfig(func(config *Config) error {
+		if _, ok := config.Profiles[name]; !ok {
+			return fmt.Errorf("profile %q does not exist", name)
 		}
-		return writeSettings(&defaultSettings, settingsFilePath)
+
+		config.ActiveProfile = name
+		return nil
+	})
+}
+
+func RemoveProfile(name string) error {
+	if name == DefaultProfile {
+		return fmt.Errorf("the %q profile cannot be removed", DefaultProfile)
 	}
 
+	return updateConfig(func(config *Config) error {
+		if _, ok := config.Profiles[name]; !ok {
+			return fmt.Errorf("profile %q does not exist", name)
+		}
+
+		delete(config.Profiles, name)
+		if config.ActiveProfile == name {
+			config.ActiveProfile = DefaultProfile
+		}
+		return nil
+	})
+}
+
+// checks that the config file and the project file can be read and parsed
+func CheckSettingsFile() error {
+	if _, err := loadConfig(); err != nil {
+		return err
+	}
+	if _, err := LoadProjectConfig(); err != nil {
+		return fmt.Errorf("project file %s: %w", ProjectFileName, err)
+	}
 	return nil
 }
 
-func getSettingsFilePath() (string, error) {
-	homeDir, err := os.UserHomeDir()
+func getCurrentProfileConfig() (*ProfileConfig, error) {
+	name, err := CurrentProfile()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	appDir := filepath.Join(homeDir, NotiDBAppDir)
-	return filepath.Join(appDir, SettingsFileName), nil
-}
 
-func readSettings(filePath string) (*UserSettings, error) {
-	file, err := os.ReadFile(filePath)
+	config, err := loadConfig()
 	if err != nil {
 		return nil, err
 	}
-	var settings UserSettings
-	err = json.Unmarshal(file, &settings)
-	return &settings, err
+
+	profile, ok := config.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile %q does not exist, create it with `notidb profile add %s`", name, name)
+	}
+	return profile, nil
 }
 
-func writeSettings(settings *UserSettings, filePath string) error {
-	data, err := json.Marshal(settings)
+func updateCurrentProfileConfig(update func(profile *ProfileConfig) error) error {
+	name, err := CurrentProfile()
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(filePath, data, FilePermMode)
+
+	return updateConfig(func(config *Config) error {
+		profile, ok := config.Profiles[name]
+		if !ok {
+			return fmt.Errorf("profile %q does not exist, create it with `notidb profile add %s`", name, name)
+		}
+
+		return update(profile)
+	})
 }
@@ -0,0 +1,27 @@
+package settings
+
+import "os"
+
+const ThemeEnvVar = "NOTIDB_THEME"
+
+// returns the selected theme name, NOTIDB_THEME env var takes precedence over the config,
+// and the user-defined themes
+func GetTheme() (string, map[string]*ThemeConfig, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return "", nil, err
+	}
+
+	name := config.Theme
+	if env := os.Getenv(ThemeEnvVar); env != "" {
+		name = env
+	}
+	return name, config.Themes, nil
+}
+
+func SetTheme(name string) error {
+	return updateConfig(func(config *Config) error {
+		config.Theme = name
+		return nil
+	})
+}
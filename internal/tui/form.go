@@ -3,42 +3,104 @@ package tui
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/ChmaraX/notidb/internal/editor"
 	"github.com/ChmaraX/notidb/internal/notion"
+	"github.com/ChmaraX/notidb/internal/settings"
 	"github.com/ChmaraX/notidb/internal/utils"
+	"github.com/ChmaraX/notidb/internal/validation"
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
-	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 	"github.com/jomei/notionapi"
 )
 
-func InitForm(schema notionapi.PropertyConfigs) notion.DatabaseEntry {
-	props := filterSupportedProps(schema)
-	model, err := tea.NewProgram(initialModel(props)).Run()
+// raw text of the form inputs, used to pre-fill the form and to save templates
+type FormValues struct {
+	// keyed by property name
+	Props map[string]string
+	// Markdown content
+	Body string
+}
+
+func (v FormValues) equal(other FormValues) bool {
+	if v.Body != other.Body {
+		return false
+	}
+	// empty inputs are left out of the values
+	for name, value := range v.Props {
+		if value != other.Props[name] {
+			return false
+		}
+	}
+	for name, value := range other.Props {
+		if value != v.Props[name] {
+			return false
+		}
+	}
+	return true
+}
+
+type FormOptions struct {
+	Values FormValues
+	Layout settings.FormConfig
+	// called periodically and on save with the current inputs, nil disables autosave
+	OnAutosave func(values FormValues) error
+	// called when the user confirms discarding the entry on quit
+	OnDiscard func() error
+}
+
+func InitForm(schema notionapi.PropertyConfigs, opts FormOptions) notion.DatabaseEntry {
+	return runForm(initialModel(filterSupportedProps(schema), opts)).entry
+}
+
+// runs the form without converting the inputs, returns false when the form was quit without saving,
+// hidden fields are shown and required ones are not enforced, as templates can fill any of them
+func InitTemplateForm(schema notionapi.PropertyConfigs, opts FormOptions) (FormValues, bool) {
+	templateLayout := settings.FormConfig{Order: opts.Layout.Order, Fields: make(map[string]*settings.FieldConfig)}
+	for name, field := range opts.Layout.Fields {
+		if field != nil {
+			templateLayout.Fields[name] = &settings.FieldConfig{Label: field.Label, Placeholder: field.Placeholder}
+		}
+	}
+	opts.Layout = templateLayout
+
+	m := initialModel(filterSupportedProps(schema), opts)
+	m.rawValues = true
+
+	m = runForm(m)
+	return m.values, m.saved
+}
+
+func runForm(m formModel) formModel {
+	if plain {
+		return runPlainForm(m)
+	}
+
+	detectBackground()
+	model, err := tea.NewProgram(m).Run()
 
 	if err != nil {
 		fmt.Println("Error running program:", err)
 		os.Exit(1)
 	}
 
-	return model.(formModel).entry
+	return model.(formModel)
 }
 
-const (
-	hotPink  = lipgloss.Color("#FF06B7")
-	darkGray = lipgloss.Color("#767676")
-	darkRed  = lipgloss.Color("#E05252")
-)
+const autosaveInterval = 5 * time.Second
 
-var (
-	inputStyle = lipgloss.NewStyle().Foreground(hotPink).MarginLeft(2)
-	errorStyle = lipgloss.NewStyle().Foreground(darkRed).MarginLeft(2)
-)
+type autosaveMsg struct{}
+
+type editorFinishedMsg struct {
+	filePath string
+	err      error
+}
 
 var validators = map[notionapi.PropertyType]textinput.ValidateFunc{
 	notionapi.PropertyTypeNumber:   numberValidator,
@@ -54,35 +116,67 @@ var placeholders = map[notionapi.PropertyType]string{
 	notionapi.PropertyTypeNumber:      "123",
 	notionapi.PropertyTypeEmail:       "example@email.com",
 	notionapi.PropertyTypePhoneNumber: "+48 123 456 789",
+	notionapi.PropertyTypeURL:         "https://example.com",
 }
 
 type formModel struct {
-	entry        notion.DatabaseEntry
-	props        []PropInput
-	block        BlockInput
+	entry notion.DatabaseEntry
+	// the raw inputs are saved instead of the entry, e.g. for templates
+	rawValues bool
+	values    FormValues
+	saved     bool
+	opts      FormOptions
+	// inputs as last autosaved, starting with the pre-filled ones
+	lastSaved FormValues
+	// the user is asked whether to discard the entry
+	confirmQuit bool
+	// terminal size, zero until the first tea.WindowSizeMsg
+	width  int
+	height int
+	// layout of the props, see formlayout.go
+	columns     int
+	propsHeight int
+	propsOffset int
+	props       []PropInput
+	// inputs of hidden fields, they keep the default and template values
+	hidden []PropInput
+	blocks []BlockInput
+	// block shown expanded, the last focused one
+	activeBlock int
+	// the language input of the active code block is focused
+	langFocused  bool
 	focusedProp  int
 	focusOnProps bool
-	err          error
-	help         help.Model
-	keymap       keymap
+	// height of the content area, zero until the terminal size is known
+	contentHeight int
+	showPreview   bool
+	err           error
+	help          help.Model
+	keymap        keymap
 }
 
 type PropInput struct {
 	propType notionapi.PropertyType
 	model    textinput.Model
 	title    string
-}
-
-type BlockInput struct {
-	propType notionapi.Block
-	model    textarea.Model
+	label    string
+	field    *settings.FieldConfig
+	// set when the value fails validation on leaving the input or on save
+	err error
 }
 
 type keymap struct {
-	save key.Binding
-	next key.Binding
-	prev key.Binding
-	quit key.Binding
+	save          key.Binding
+	edit          key.Binding
+	next          key.Binding
+	prev          key.Binding
+	addBlock      key.Binding
+	blockType     key.Binding
+	moveBlockUp   key.Binding
+	moveBlockDown key.Binding
+	removeBlock   key.Binding
+	preview       key.Binding
+	quit          key.Binding
 }
 
 func (m formModel) toDatabaseEntry() (notion.DatabaseEntry, error) {
@@ -91,7 +185,7 @@ func (m formModel) toDatabaseEntry() (notion.DatabaseEntry, error) {
 		Blocks: make([]notionapi.Block, 0),
 	}
 
-	for _, prop := range m.props {
+	for _, prop := range m.allProps() {
 		propTitle := prop.title
 		propValue := prop.model.Value()
 
@@ -99,51 +193,116 @@ func (m formModel) toDatabaseEntry() (notion.DatabaseEntry, error) {
 			continue
 		}
 
-		switch prop.propType {
-		case notionapi.PropertyTypeTitle:
-			entry.Props[propTitle] = notion.CreateTitleProperty(propValue)
-		case notionapi.PropertyTypeRichText:
-			entry.Props[propTitle] = notion.CreateRichTextProperty(propValue)
-		case notionapi.PropertyTypeSelect:
-			entry.Props[propTitle] = notion.CreateSelectProperty(propValue)
-		case notionapi.PropertyTypeMultiSelect:
-			v := strings.Split(propValue, ",")
-			entry.Props[propTitle] = notion.CreateMultiSelectProperty(v)
-		case notionapi.PropertyTypeDate:
-			date, err := notion.CreateDateProperty(propValue)
-			if err != nil {
-				return notion.DatabaseEntry{}, fmt.Errorf("failed to parse date: %w", err)
-			}
-			entry.Props[propTitle] = date
-		case notionapi.PropertyTypeCheckbox:
-			v, _ := utils.ParseBool(propValue)
-			entry.Props[propTitle] = notion.CreateCheckboxProperty(v)
-		case notionapi.PropertyTypeNumber:
-			v, _ := strconv.ParseFloat(propValue, 64)
-			entry.Props[propTitle] = notion.CreateNumberProperty(v)
-		case notionapi.PropertyTypeEmail:
-			entry.Props[propTitle] = notion.CreateEmailProperty(propValue)
-		case notionapi.PropertyTypePhoneNumber:
-			entry.Props[propTitle] = notion.CreatePhoneNumberProperty(propValue)
-		default:
-			fmt.Printf("unsupported property type: %s", prop.propType)
+		property, err := notion.CreateProperty(prop.propType, propValue)
+		if err != nil {
+			return notion.DatabaseEntry{}, fmt.Errorf("%s: %w", prop.label, err)
 		}
+		entry.Props[propTitle] = property
 	}
 
-	entry.Blocks = append(entry.Blocks, notion.CreateContentBlock(m.block.model.Value()))
+	entry.Blocks = append(entry.Blocks, m.contentBlocks()...)
 
 	return entry, nil
 }
 
-// filter props supported by the TUI form
-func filterSupportedProps(schema notionapi.PropertyConfigs) map[string]notionapi.PropertyType {
-	supportedPropTypes := notion.GetSupportedPropTypes()
+// sets the value even if the input validator would reject it, so the error is shown instead
+func (p *PropInput) setValue(value string) {
+	validate := p.model.Validate
+	p.model.Validate = nil
+	p.model.SetValue(value)
+	p.model.Validate = validate
+	p.validate()
+}
 
-	// Convert slice to map for faster lookup
-	supportedPropTypesMap := make(map[string]bool)
-	for _, propType := range supportedPropTypes {
-		supportedPropTypesMap[string(propType)] = true
+func (p *PropInput) validate() error {
+	p.err = validation.Validate(p.propType, p.model.Value(), p.field)
+	return p.err
+}
+
+// validates all inputs, including hidden ones, returns the first error
+func (m *formModel) validate() error {
+	var firstErr error
+	for i := range m.props {
+		if err := m.props[i].validate(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("%s: %w", m.props[i].label, err)
+		}
 	}
+	for i := range m.hidden {
+		if err := m.hidden[i].validate(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("%s (hidden): %w", m.hidden[i].label, err)
+		}
+	}
+	return firstErr
+}
+
+func (m formModel) allProps() []PropInput {
+	props := make([]PropInput, 0, len(m.props)+len(m.hidden))
+	props = append(props, m.props...)
+	return append(props, m.hidden...)
+}
+
+func (m formModel) toFormValues() FormValues {
+	values := FormValues{Props: make(map[string]string), Body: m.bodyMarkdown()}
+	for _, prop := range m.allProps() {
+		if prop.model.Value() != "" {
+			values.Props[prop.title] = prop.model.Value()
+		}
+	}
+	return values
+}
+
+// returns the prop names in the form order: the title, the props listed in the order,
+// then the rest alphabetically, the title can be moved by listing it
+func orderProps(props map[string]notionapi.PropertyType, order []string) []string {
+	names := make([]string, 0, len(props))
+	added := make(map[string]bool)
+
+	listed := make(map[string]bool)
+	for _, name := range order {
+		listed[name] = true
+	}
+
+	var rest []string
+	for name, propType := range props {
+		if propType == notionapi.PropertyTypeTitle && !listed[name] {
+			names = append(names, name)
+			added[name] = true
+		} else if !listed[name] {
+			rest = append(rest, name)
+		}
+	}
+
+	for _, name := range order {
+		if _, ok := props[name]; ok && !added[name] {
+			names = append(names, name)
+			added[name] = true
+		}
+	}
+
+	sort.Strings(rest)
+	return append(names, rest...)
+}
+
+// returns the names of the props shown in the form, in the form order
+func FormFields(schema notionapi.PropertyConfigs, layout settings.FormConfig, values map[string]string) []string {
+	var names []string
+	for _, name := range orderProps(filterSupportedProps(schema), layout.Order) {
+		if !isHiddenField(layout.Fields[name], values[name]) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// required fields are shown while they are empty, even when configured as hidden,
+// otherwise the form could never be saved
+func isHiddenField(field *settings.FieldConfig, value string) bool {
+	return field != nil && field.Hidden && !(field.Required && value == "")
+}
+
+// filter props supported by the TUI form
+func filterSupportedProps(schema notionapi.PropertyConfigs) map[string]notionapi.PropertyType {
+	supportedPropTypesMap := getSupportedPropTypesMap()
 
 	props := make(map[string]notionapi.PropertyType)
 	for key, prop := range schema {
@@ -156,6 +315,36 @@ func filterSupportedProps(schema notionapi.PropertyConfigs) map[string]notionapi
 	return props
 }
 
+// returns props which are skipped by the TUI form because their type is not supported,
+// props computed by Notion are left out as they can't be set anyway
+func SkippedProps(schema notionapi.PropertyConfigs) map[string]notionapi.PropertyType {
+	supportedPropTypesMap := getSupportedPropTypesMap()
+
+	props := make(map[string]notionapi.PropertyType)
+	for key, prop := range schema {
+		propType := notionapi.PropertyType(prop.GetType())
+		if notion.IsComputedProperty(propType) {
+			continue
+		}
+		if _, ok := supportedPropTypesMap[string(propType)]; !ok {
+			props[key] = notionapi.PropertyType(prop.GetType())
+		}
+	}
+
+	return props
+}
+
+func getSupportedPropTypesMap() map[string]bool {
+	supportedPropTypes := notion.GetSupportedPropTypes()
+
+	// Convert slice to map for faster lookup
+	supportedPropTypesMap := make(map[string]bool)
+	for _, propType := range supportedPropTypes {
+		supportedPropTypesMap[string(propType)] = true
+	}
+	return supportedPropTypesMap
+}
+
 func numberValidator(s string) error {
 	_, err := strconv.ParseFloat(s, 64)
 	if err != nil && s != "" {
@@ -172,67 +361,70 @@ func checkboxValidator(s string) error {
 	return nil
 }
 
-func createPropInput(title string, propType notionapi.PropertyType) PropInput {
+func createPropInput(title string, propType notionapi.PropertyType, value string, field *settings.FieldConfig) PropInput {
 	ti := textinput.New()
 	ti.Placeholder = placeholders[propType]
+	// set before the validator, so an invalid pre-filled value is reported on save instead of dropped
+	ti.SetValue(value)
 	ti.Validate = validators[propType]
 
-	return PropInput{
+	pi := PropInput{
 		propType: propType,
 		model:    ti,
 		title:    title,
+		label:    title,
+		field:    field,
+	}
+
+	if field != nil {
+		if field.Label != "" {
+			pi.label = field.Label
+		}
+		if field.Placeholder != "" {
+			pi.model.Placeholder = field.Placeholder
+		}
 	}
+
+	return pi
 }
 
-func createBlockInput() BlockInput {
-	ta := textarea.New()
-	ta.Placeholder = "Start writing..."
-	ta.SetWidth(50)
-	ta.SetHeight(10)
-
-	return BlockInput{
-		propType: notionapi.ParagraphBlock{},
-		model:    ta,
-	}
-}
-
-func initialModel(props map[string]notionapi.PropertyType) formModel {
-	propInputs := make([]PropInput, len(props))
-
-	titleIdx := 0 // title is always first
-	idx := 1
-	for title, propType := range props {
-
-		switch propType {
-		case notionapi.PropertyTypeTitle:
-			pi := createPropInput(title, propType)
-			pi.model.Focus()
-			propInputs[titleIdx] = pi
-		case
-			notionapi.PropertyTypeRichText,
-			notionapi.PropertyTypeSelect,
-			notionapi.PropertyTypeMultiSelect,
-			notionapi.PropertyTypeDate,
-			notionapi.PropertyTypeCheckbox,
-			notionapi.PropertyTypeNumber,
-			notionapi.PropertyTypeEmail,
-			notionapi.PropertyTypePhoneNumber:
-			propInputs[idx] = createPropInput(title, propType)
-			idx++
-		default:
-			fmt.Printf("unsupported property type: %s", propType)
+func initialModel(props map[string]notionapi.PropertyType, opts FormOptions) formModel {
+	values, layout := opts.Values, opts.Layout
+	var propInputs, hiddenInputs []PropInput
+
+	for _, title := range orderProps(props, layout.Order) {
+		field := layout.Fields[title]
+		pi := createPropInput(title, props[title], values.Props[title], field)
+
+		if isHiddenField(field, values.Props[title]) {
+			hiddenInputs = append(hiddenInputs, pi)
+			continue
 		}
+		propInputs = append(propInputs, pi)
+	}
+
+	// pre-filled content is Markdown, e.g. from templates and drafts
+	block := createBlockInput(markdownBlock, values.Body)
+	focusOnProps := len(propInputs) > 0
+	if focusOnProps {
+		propInputs[0].model.Focus()
+	} else {
+		block.model.Focus()
 	}
 
 	// help styles
 	help := help.New()
-	help.Styles.ShortKey = lipgloss.NewStyle().Foreground(darkGray)
+	help.Styles.ShortKey = helpKeyStyle
 
 	return formModel{
 		props:        propInputs,
-		block:        createBlockInput(),
+		hidden:       hiddenInputs,
+		blocks:       []BlockInput{block},
 		focusedProp:  0,
-		focusOnProps: true,
+		focusOnProps: focusOnProps,
+		columns:      1,
+		opts:         opts,
+		lastSaved:    values,
 		err:          nil,
 		keymap:       getHelpKeyMap(),
 		help:         help,
@@ -241,28 +433,39 @@ func initialModel(props map[string]notionapi.PropertyType) formModel {
 
 func getHelpKeyMap() keymap {
 	return keymap{
-		save: key.NewBinding(
-			key.WithKeys("ctrl+s"),
-			key.WithHelp("<ctrl+s>", "save"),
-		),
-		next: key.NewBinding(
-			key.WithKeys("tab", "ctrl+n"),
-			key.WithHelp("<tab>", "next"),
-		),
-		prev: key.NewBinding(
-			key.WithKeys("shift+tab", "ctrl+p"),
-			key.WithHelp("<shift+tab>", "previous"),
-		),
-		quit: key.NewBinding(
-			key.WithKeys("ctrl+c"),
-			key.WithHelp("<ctrl+c>", "quit"),
-		),
+		save:          newBinding(FormKeys, "save", "save"),
+		edit:          newBinding(FormKeys, "edit", "editor"),
+		addBlock:      newBinding(FormKeys, "add_block", "new block"),
+		blockType:     newBinding(FormKeys, "block_type", "type"),
+		moveBlockUp:   newPairBinding(FormKeys, "move_block_up", "move_block_down", "move"),
+		moveBlockDown: newBinding(FormKeys, "move_block_down", "move down"),
+		removeBlock:   newBinding(FormKeys, "remove_block", "remove"),
+		preview:       newBinding(FormKeys, "preview", "preview"),
+		next:          newBinding(FormKeys, "next", "next"),
+		prev:          newBinding(FormKeys, "prev", "previous"),
+		quit:          newBinding(FormKeys, "quit", "quit"),
 	}
 }
 
 func (m formModel) helpView() string {
+	if !m.focusOnProps {
+		return m.help.ShortHelpView([]key.Binding{
+			m.keymap.save,
+			m.keymap.edit,
+			m.keymap.preview,
+			m.keymap.addBlock,
+			m.keymap.blockType,
+			m.keymap.moveBlockUp,
+			m.keymap.removeBlock,
+			m.keymap.next,
+			m.keymap.quit,
+		})
+	}
 	return m.help.ShortHelpView([]key.Binding{
 		m.keymap.save,
+		m.keymap.edit,
+		m.keymap.preview,
+		m.keymap.addBlock,
 		m.keymap.next,
 		m.keymap.prev,
 		m.keymap.quit,
@@ -270,44 +473,212 @@ func (m formModel) helpView() string {
 }
 
 func (m formModel) Init() tea.Cmd {
-	return textinput.Blink
+	if m.opts.OnAutosave == nil {
+		return textinput.Blink
+	}
+	return tea.Batch(textinput.Blink, autosaveTick())
 }
 
-func (m formModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	var cmds []tea.Cmd = make([]tea.Cmd, len(m.props)+1) // +1 for block input
+func autosaveTick() tea.Cmd {
+	return tea.Tick(autosaveInterval, func(time.Time) tea.Msg {
+		return autosaveMsg{}
+	})
+}
+
+// saves the inputs if they changed since the last autosave
+func (m *formModel) autosave() {
+	if m.opts.OnAutosave == nil {
+		return
+	}
+	values := m.toFormValues()
+	if values.equal(m.lastSaved) {
+		return
+	}
+	if err := m.opts.OnAutosave(values); err != nil {
+		m.err = fmt.Errorf("autosave failed: %w", err)
+		return
+	}
+	m.lastSaved = values
+}
+
+// opens the content in $EDITOR, with the visible props as front matter,
+// the edited content replaces all blocks with a single Markdown one
+func (m *formModel) openEditor() tea.Cmd {
+	fields := make([]editor.Field, len(m.props))
+	for i, prop := range m.props {
+		fields[i] = editor.Field{Name: prop.title, Value: prop.model.Value()}
+	}
 
+	content, err := editor.Marshal(fields, m.bodyMarkdown())
+	if err != nil {
+		m.err = err
+		return nil
+	}
+	filePath, err := editor.WriteTempFile(content)
+	if err != nil {
+		m.err = err
+		return nil
+	}
+
+	return tea.ExecProcess(editor.Command(filePath), func(err error) tea.Msg {
+		return editorFinishedMsg{filePath: filePath, err: err}
+	})
+}
+
+func (m *formModel) readEditedFile(msg editorFinishedMsg) {
+	content, err := editor.ReadTempFile(msg.filePath)
+	if msg.err != nil {
+		m.err = fmt.Errorf("editor failed: %w", msg.err)
+		return
+	}
+	if err != nil {
+		m.err = err
+		return
+	}
+
+	values, body, err := editor.Unmarshal(content)
+	if err != nil {
+		m.err = err
+		return
+	}
+
+	m.err = nil
+	// hidden props are not written to the file, but they can still be added to it
+	for _, inputs := range [][]PropInput{m.props, m.hidden} {
+		for i := range inputs {
+			if value, ok := values[inputs[i].title]; ok {
+				inputs[i].setValue(value)
+				delete(values, inputs[i].title)
+			}
+		}
+	}
+	for name := range values {
+		m.err = fmt.Errorf("unknown property %q in the front matter", name)
+	}
+	m.replaceBlocks(body)
+}
+
+func (m formModel) updateConfirmQuit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.String() == "y":
+		if m.opts.OnDiscard != nil {
+			if err := m.opts.OnDiscard(); err != nil {
+				m.err = fmt.Errorf("failed to discard the draft: %w", err)
+				m.confirmQuit = false
+				return m, nil
+			}
+		}
+		return m, tea.Quit
+	// pressing quit again keeps the draft
+	case msg.String() == "d" || key.Matches(msg, m.keymap.quit):
+		m.autosave()
+		return m, tea.Quit
+	case msg.String() == "n" || msg.String() == "esc":
+		m.confirmQuit = false
+	}
+	return m, nil
+}
+
+func (m formModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case autosaveMsg:
+		m.autosave()
+		return m, autosaveTick()
+	case editorFinishedMsg:
+		m.readEditedFile(msg)
+		return m, nil
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.resize()
+		return m, nil
 	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyCtrlS:
+		if m.confirmQuit {
+			return m.updateConfirmQuit(msg)
+		}
+
+		switch {
+		case key.Matches(msg, m.keymap.save):
+			if m.rawValues {
+				m.values = m.toFormValues()
+				m.saved = true
+				return m, tea.Quit
+			}
+			if err := m.validate(); err != nil {
+				m.err = err
+				return m, nil
+			}
 			entry, err := m.toDatabaseEntry()
 			if err != nil {
 				m.err = err
 				return m, nil
 			}
+			// kept until the entry is saved to Notion, so it can be resumed if that fails
+			m.autosave()
 			m.entry = entry
 			return m, tea.Quit
-		case tea.KeyCtrlC:
-			return m, tea.Quit
-		case tea.KeyShiftTab, tea.KeyCtrlP:
+		case key.Matches(msg, m.keymap.edit):
+			return m, m.openEditor()
+		case key.Matches(msg, m.keymap.quit):
+			// nothing typed, nothing to lose
+			if m.rawValues || m.opts.OnAutosave == nil || m.toFormValues().equal(m.opts.Values) {
+				return m, tea.Quit
+			}
+			m.confirmQuit = true
+			return m, nil
+		// the keys aren't passed to the newly focused input
+		case key.Matches(msg, m.keymap.prev):
 			m.prevInput()
-		case tea.KeyTab, tea.KeyCtrlN:
+			return m, nil
+		case key.Matches(msg, m.keymap.next):
 			m.nextInput()
+			return m, nil
+		case key.Matches(msg, m.keymap.preview):
+			m.showPreview = !m.showPreview
+			m.resizeBlocks()
+			return m, nil
+		case key.Matches(msg, m.keymap.addBlock):
+			m.addBlock()
+			return m, nil
+		case m.focusOnProps:
+			// the other block keys act on the active block only while it's focused
+		case key.Matches(msg, m.keymap.blockType):
+			m.cycleBlockType()
+			return m, nil
+		case key.Matches(msg, m.keymap.moveBlockUp):
+			m.moveBlock(-1)
+			return m, nil
+		case key.Matches(msg, m.keymap.moveBlockDown):
+			m.moveBlock(1)
+			return m, nil
+		case key.Matches(msg, m.keymap.removeBlock):
+			m.removeBlock()
+			return m, nil
 		}
 	}
 
 	// Update each element and collect commands
+	cmds := make([]tea.Cmd, 0, len(m.props)+2*len(m.blocks))
 	for i := range m.props {
-		m.props[i].model, cmds[i] = m.props[i].model.Update(msg)
+		var cmd tea.Cmd
+		m.props[i].model, cmd = m.props[i].model.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	for i := range m.blocks {
+		var modelCmd, langCmd tea.Cmd
+		m.blocks[i].model, modelCmd = m.blocks[i].model.Update(msg)
+		m.blocks[i].lang, langCmd = m.blocks[i].lang.Update(msg)
+		cmds = append(cmds, modelCmd, langCmd)
 	}
-	m.block.model, cmds[len(m.props)] = m.block.model.Update(msg)
 
 	return m, tea.Batch(cmds...)
 }
 
-func getElemErrMsg(input textinput.Model) string {
-	if input.Err != nil {
-		return errorStyle.Render(input.Err.Error())
+func getElemErrMsg(prop PropInput) string {
+	if prop.err != nil {
+		return errorStyle.Render(prop.err.Error())
+	}
+	if prop.model.Err != nil {
+		return errorStyle.Render(prop.model.Err.Error())
 	}
 	return ""
 }
@@ -315,65 +686,104 @@ func getElemErrMsg(input textinput.Model) string {
 func (m formModel) View() string {
 	var inputsView strings.Builder
 
-	for _, value := range m.props {
-		input := value.model
-		inputsView.WriteString(fmt.Sprintf("%s%s%s\n", inputStyle.Width(15).Render(value.title), input.View(), getElemErrMsg(input)))
-	}
-
-	inputsView.WriteString(fmt.Sprintf("\n%s\n%s\n", inputStyle.Width(30).Render("Content"), m.block.model.View()))
+	inputsView.WriteString(m.propsView())
+	inputsView.WriteString(fmt.Sprintf("\n%s\n%s", inputStyle.Width(30).Render("Content"), m.contentView()))
 
 	if m.err != nil {
 		inputsView.WriteString(fmt.Sprintf("\n%s\n", errorStyle.Render(m.err.Error())))
 	}
 
-	return fmt.Sprintf("\n%s\n%s\n\n", inputsView.String(), m.helpView())
+	if m.confirmQuit {
+		return fmt.Sprintf("%s\n%s\n", inputsView.String(), inputStyle.Render("Discard this entry? <y> discard  <d> keep as draft  <n> continue editing"))
+	}
+
+	return fmt.Sprintf("%s\n%s\n", inputsView.String(), m.helpView())
 }
 
-func (m *formModel) nextInput() {
-	m.blurCurrentElement()
+// element which can take the focus, a prop input or a block with its language input
+type focusTarget struct {
+	prop  int
+	block int
+	lang  bool
+}
 
-	if m.focusOnProps {
-		m.focusedProp++
-		if m.focusedProp >= len(m.props) {
-			m.focusOnProps = false
-			m.focusedProp = 0
+// returns the focusable elements in the tab order: props, then blocks,
+// code blocks with their language first
+func (m formModel) focusTargets() []focusTarget {
+	var targets []focusTarget
+	for i := range m.props {
+		targets = append(targets, focusTarget{prop: i, block: -1})
+	}
+	for i, block := range m.blocks {
+		if block.blockType == codeBlock {
+			targets = append(targets, focusTarget{prop: -1, block: i, lang: true})
 		}
-	} else {
-		m.focusOnProps = true
+		targets = append(targets, focusTarget{prop: -1, block: i})
 	}
+	return targets
+}
 
-	m.focusCurrentElement()
+func (m formModel) currentTarget() focusTarget {
+	if m.focusOnProps {
+		return focusTarget{prop: m.focusedProp, block: -1}
+	}
+	return focusTarget{prop: -1, block: m.activeBlock, lang: m.langFocused}
 }
 
-func (m *formModel) prevInput() {
-	m.blurCurrentElement()
+func (m *formModel) moveFocus(step int) {
+	targets := m.focusTargets()
+	current := m.currentTarget()
 
-	if m.focusOnProps {
-		if m.focusedProp == 0 {
-			m.focusOnProps = false
-		} else {
-			m.focusedProp--
+	idx := 0
+	for i, target := range targets {
+		if target == current {
+			idx = i
 		}
-	} else {
-		m.focusOnProps = true
-		m.focusedProp = len(m.props) - 1
 	}
+	next := targets[(idx+step+len(targets))%len(targets)]
 
+	m.blurCurrentElement()
+	m.focusOnProps = next.prop >= 0
+	if m.focusOnProps {
+		m.focusedProp = next.prop
+	} else {
+		m.activeBlock = next.block
+		m.langFocused = next.lang
+		m.resizeBlocks()
+	}
 	m.focusCurrentElement()
 }
 
+func (m *formModel) nextInput() {
+	m.moveFocus(1)
+}
+
+func (m *formModel) prevInput() {
+	m.moveFocus(-1)
+}
+
 func (m *formModel) blurCurrentElement() {
-	if m.focusOnProps {
+	switch {
+	case m.focusOnProps:
 		m.props[m.focusedProp].model.Blur()
-	} else {
-		m.block.model.Blur()
+		m.props[m.focusedProp].validate()
+	case m.langFocused:
+		m.blocks[m.activeBlock].lang.Blur()
+	default:
+		m.blocks[m.activeBlock].model.Blur()
 	}
 }
 
 func (m *formModel) focusCurrentElement() {
-	if m.focusOnProps {
+	if m.width > 0 {
+		m.scrollToFocused()
+	}
+	switch {
+	case m.focusOnProps:
 		m.props[m.focusedProp].model.Focus()
-	} else {
-		m.block.model.Focus()
+	case m.langFocused:
+		m.blocks[m.activeBlock].lang.Focus()
+	default:
+		m.blocks[m.activeBlock].model.Focus()
 	}
 }
@@ -0,0 +1,336 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ChmaraX/notidb/internal/notion"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/jomei/notionapi"
+)
+
+const (
+	markdownBlock  = "markdown"
+	paragraphBlock = "paragraph"
+	heading1Block  = "heading 1"
+	heading2Block  = "heading 2"
+	heading3Block  = "heading 3"
+	toDoBlock      = "to-do"
+	bulletBlock    = "bullet"
+	codeBlock      = "code"
+	quoteBlock     = "quote"
+	calloutBlock   = "callout"
+)
+
+// block types in the order they are cycled through, Markdown content is converted
+// into as many blocks as needed
+var blockTypes = []string{
+	markdownBlock,
+	paragraphBlock,
+	heading1Block,
+	heading2Block,
+	heading3Block,
+	toDoBlock,
+	bulletBlock,
+	codeBlock,
+	quoteBlock,
+	calloutBlock,
+}
+
+var blockPlaceholders = map[string]string{
+	markdownBlock: "Start writing... (Markdown)",
+	toDoBlock:     "One to-do per line",
+	bulletBlock:   "One item per line",
+}
+
+const (
+	minBlockHeight = 3
+	langInputWidth = 15
+)
+
+type BlockInput struct {
+	blockType string
+	model     textarea.Model
+	// language of code blocks
+	lang textinput.Model
+}
+
+func createBlockInput(blockType, value string) BlockInput {
+	ta := textarea.New()
+	// longer content is written in the editor, it mustn't be cut off
+	ta.CharLimit = 0
+	ta.MaxHeight = 0
+	ta.SetWidth(50)
+	ta.SetHeight(10)
+	ta.SetValue(value)
+
+	lang := textinput.New()
+	lang.Placeholder = "plain text"
+	lang.Width = langInputWidth
+
+	b := BlockInput{
+		blockType: blockType,
+		model:     ta,
+		lang:      lang,
+	}
+	b.setType(blockType)
+	return b
+}
+
+func (b *BlockInput) setType(blockType string) {
+	b.blockType = blockType
+	b.model.Placeholder = "Start writing..."
+	if placeholder, ok := blockPlaceholders[blockType]; ok {
+		b.model.Placeholder = placeholder
+	}
+}
+
+// switches to the next block type, or the previous one for a negative step
+func (b *BlockInput) cycleType(step int) {
+	idx := 0
+	for i, blockType := range blockTypes {
+		if blockType == b.blockType {
+			idx = i
+		}
+	}
+	idx = (idx + step + len(blockTypes)) % len(blockTypes)
+	b.setType(blockTypes[idx])
+}
+
+func (b BlockInput) isEmpty() bool {
+	return strings.TrimSpace(b.model.Value()) == ""
+}
+
+// returns the Notion blocks of the input, none when it's empty
+func (b BlockInput) toBlocks() []notionapi.Block {
+	if b.isEmpty() {
+		return nil
+	}
+	content := b.model.Value()
+
+	switch b.blockType {
+	case paragraphBlock:
+		return []notionapi.Block{notion.CreateParagraphBlock(content)}
+	case heading1Block, heading2Block, heading3Block:
+		return []notionapi.Block{notion.CreateHeadingBlock(headingLevel(b.blockType), joinLines(content))}
+	case toDoBlock:
+		var blocks []notionapi.Block
+		for _, line := range nonEmptyLines(content) {
+			blocks = append(blocks, notion.CreateToDoBlock(line, false))
+		}
+		return blocks
+	case bulletBlock:
+		var blocks []notionapi.Block
+		for _, line := range nonEmptyLines(content) {
+			blocks = append(blocks, notion.CreateBulletedListItemBlock(line))
+		}
+		return blocks
+	case codeBlock:
+		return []notionapi.Block{notion.CreateCodeBlock(content, b.lang.Value())}
+	case quoteBlock:
+		return []notionapi.Block{notion.CreateQuoteBlock(content)}
+	case calloutBlock:
+		return []notionapi.Block{notion.CreateCalloutBlock(content)}
+	}
+	return notion.MarkdownToBlocks(content)
+}
+
+// returns the content as Markdown, which converts back into the same blocks,
+// used for drafts, templates and the editor
+func (b BlockInput) toMarkdown() string {
+	if b.isEmpty() {
+		return ""
+	}
+	content := b.model.Value()
+
+	switch b.blockType {
+	case heading1Block, heading2Block, heading3Block:
+		return strings.Repeat("#", headingLevel(b.blockType)) + " " + joinLines(content)
+	case toDoBlock:
+		return prefixLines(nonEmptyLines(content), "- [ ] ")
+	case bulletBlock:
+		return prefixLines(nonEmptyLines(content), "- ")
+	case codeBlock:
+		fence := notion.CodeFence(content)
+		return fmt.Sprintf("%s%s\n%s\n%s", fence, strings.TrimSpace(b.lang.Value()), content, fence)
+	case quoteBlock:
+		return prefixLines(strings.Split(content, "\n"), "> ")
+	case calloutBlock:
+		return "> [!NOTE] " + prefixLines(strings.Split(content, "\n"), "> ")[len("> "):]
+	}
+	return content
+}
+
+// summary of the block shown when it's not being edited
+func (b BlockInput) summary(width int) string {
+	text := "empty"
+	if !b.isEmpty() {
+		text = joinLines(b.model.Value())
+	}
+	return truncate(fmt.Sprintf("%s: %s", b.blockType, text), width)
+}
+
+func headingLevel(blockType string) int {
+	switch blockType {
+	case heading2Block:
+		return 2
+	case heading3Block:
+		return 3
+	}
+	return 1
+}
+
+func nonEmptyLines(content string) []string {
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func joinLines(content string) string {
+	return strings.Join(nonEmptyLines(content), " ")
+}
+
+func prefixLines(lines []string, prefix string) string {
+	for i, line := range lines {
+		lines[i] = prefix + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// the content of all blocks as Markdown
+func (m formModel) bodyMarkdown() string {
+	var parts []string
+	for _, block := range m.blocks {
+		if markdown := block.toMarkdown(); markdown != "" {
+			parts = append(parts, markdown)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// replaces all blocks with a single Markdown block, e.g. after editing the content in the editor
+func (m *formModel) replaceBlocks(markdown string) {
+	focused := !m.focusOnProps
+	m.blurCurrentElement()
+
+	m.blocks = []BlockInput{createBlockInput(markdownBlock, markdown)}
+	m.activeBlock = 0
+	m.langFocused = false
+	m.resizeBlocks()
+
+	if focused {
+		m.focusCurrentElement()
+	}
+}
+
+// adds an empty paragraph after the active block and focuses it
+func (m *formModel) addBlock() {
+	m.blurCurrentElement()
+
+	idx := m.activeBlock + 1
+	if len(m.blocks) == 0 {
+		idx = 0
+	}
+	m.blocks = append(m.blocks, BlockInput{})
+	copy(m.blocks[idx+1:], m.blocks[idx:])
+	m.blocks[idx] = createBlockInput(paragraphBlock, "")
+
+	m.activeBlock = idx
+	m.focusOnProps = false
+	m.langFocused = false
+	m.resizeBlocks()
+	m.focusCurrentElement()
+}
+
+// swaps the active block with the previous one, or the next one for a positive step
+func (m *formModel) moveBlock(step int) {
+	target := m.activeBlock + step
+	if target < 0 || target >= len(m.blocks) {
+		return
+	}
+	m.blocks[m.activeBlock], m.blocks[target] = m.blocks[target], m.blocks[m.activeBlock]
+	m.activeBlock = target
+}
+
+// removes the active block, the last block is kept
+func (m *formModel) removeBlock() {
+	if len(m.blocks) <= 1 {
+		return
+	}
+	m.blurCurrentElement()
+
+	m.blocks = append(m.blocks[:m.activeBlock], m.blocks[m.activeBlock+1:]...)
+	if m.activeBlock >= len(m.blocks) {
+		m.activeBlock = len(m.blocks) - 1
+	}
+	m.langFocused = false
+	m.resizeBlocks()
+	m.focusCurrentElement()
+}
+
+func (m *formModel) cycleBlockType() {
+	block := &m.blocks[m.activeBlock]
+	block.cycleType(1)
+
+	// the language input disappears with the code type
+	if m.langFocused && block.blockType != codeBlock {
+		block.lang.Blur()
+		m.langFocused = false
+		block.model.Focus()
+	}
+}
+
+// returns the Notion blocks of the content, empty blocks are skipped
+func (m formModel) contentBlocks() []notionapi.Block {
+	var blocks []notionapi.Block
+	for _, block := range m.blocks {
+		blocks = append(blocks, block.toBlocks()...)
+	}
+	return blocks
+}
+
+// the active block takes the content area left by the preview,
+// the other ones are shown as a single line
+func (m *formModel) resizeBlocks() {
+	if m.width == 0 {
+		return
+	}
+	width, height := m.blocksSize()
+	height -= len(m.blocks)
+	if height < minBlockHeight {
+		height = minBlockHeight
+	}
+	for i := range m.blocks {
+		m.blocks[i].model.SetWidth(width)
+	}
+	m.blocks[m.activeBlock].model.SetHeight(height)
+}
+
+func (m formModel) blocksView() string {
+	var view strings.Builder
+
+	width, _ := m.blocksSize()
+	if width <= 0 {
+		width = 50
+	}
+
+	for i, block := range m.blocks {
+		if i != m.activeBlock {
+			view.WriteString(scrollHintStyle.Render(fmt.Sprintf("%d %s", i+1, block.summary(width-2))) + "\n")
+			continue
+		}
+
+		header := inputStyle.Render(fmt.Sprintf("%d %s", i+1, block.blockType))
+		if block.blockType == codeBlock {
+			header += inputStyle.Render("language") + " " + block.lang.View()
+		}
+		view.WriteString(header + "\n" + block.model.View() + "\n")
+	}
+
+	return view.String()
+}
@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ChmaraX/notidb/internal/notion"
+)
+
+// the Markdown of a block is converted back into the same blocks, drafts and templates rely on it
+func TestBlockInputMarkdownRoundTrip(t *testing.T) {
+	tests := []struct {
+		blockType string
+		value     string
+		lang      string
+	}{
+		{blockType: paragraphBlock, value: "Some text"},
+		{blockType: heading1Block, value: "Title"},
+		{blockType: heading2Block, value: "Section"},
+		{blockType: heading3Block, value: "Subsection"},
+		{blockType: toDoBlock, value: "first\n\nsecond"},
+		{blockType: bulletBlock, value: "one\ntwo"},
+		{blockType: codeBlock, value: "func main() {}", lang: "go"},
+		{blockType: codeBlock, value: "```\nfenced\n```"},
+		{blockType: codeBlock, value: "# not a heading\n\n- not a bullet"},
+		{blockType: quoteBlock, value: "quoted\nlines"},
+		{blockType: calloutBlock, value: "note\nmore"},
+		{blockType: markdownBlock, value: "# Heading\n\ntext\n- item"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.blockType, func(t *testing.T) {
+			b := createBlockInput(tt.blockType, tt.value)
+			b.lang.SetValue(tt.lang)
+
+			markdown := b.toMarkdown()
+			got, want := notion.MarkdownToBlocks(markdown), b.toBlocks()
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("blocks of %q = %#v, want %#v", markdown, got, want)
+			}
+		})
+	}
+}
+
+func TestEmptyBlockInput(t *testing.T) {
+	for _, blockType := range blockTypes {
+		b := createBlockInput(blockType, " \n ")
+		if markdown := b.toMarkdown(); markdown != "" {
+			t.Errorf("%s: toMarkdown() = %q, want empty", blockType, markdown)
+		}
+		if blocks := b.toBlocks(); blocks != nil {
+			t.Errorf("%s: toBlocks() = %v, want none", blockType, blocks)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ChmaraX/notidb/internal/settings"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jomei/notionapi"
+)
+
+// runs the TUI for reordering and toggling the form fields, returns false when quit without saving
+func InitFormConfig(schema notionapi.PropertyConfigs, layout settings.FormConfig) (settings.FormConfig, bool) {
+	m := initialFormConfigModel(filterSupportedProps(schema), layout)
+	if plain {
+		m = runPlainFormConfig(m)
+		return m.toFormConfig(), m.saved
+	}
+
+	detectBackground()
+	model, err := tea.NewProgram(m).Run()
+
+	if err != nil {
+		fmt.Println("Error running program:", err)
+		os.Exit(1)
+	}
+
+	m = model.(formConfigModel)
+	return m.toFormConfig(), m.saved
+}
+
+type formField struct {
+	title    string
+	propType notionapi.PropertyType
+	hidden   bool
+	required bool
+}
+
+type formConfigModel struct {
+	fields []formField
+	// field options set in the config only, kept as they are
+	layout   settings.FormConfig
+	cursor   int
+	saved    bool
+	help     help.Model
+	keymap   formConfigKeymap
+	quitting bool
+}
+
+type formConfigKeymap struct {
+	up       key.Binding
+	down     key.Binding
+	moveUp   key.Binding
+	moveDown key.Binding
+	hide     key.Binding
+	required key.Binding
+	save     key.Binding
+	quit     key.Binding
+}
+
+func initialFormConfigModel(props map[string]notionapi.PropertyType, layout settings.FormConfig) formConfigModel {
+	var fields []formField
+	for _, title := range orderProps(props, layout.Order) {
+		field := formField{title: title, propType: props[title]}
+		if config := layout.Fields[title]; config != nil {
+			field.hidden = config.Hidden
+			field.required = config.Required
+		}
+		fields = append(fields, field)
+	}
+
+	help := help.New()
+	help.Styles.ShortKey = helpKeyStyle
+
+	return formConfigModel{
+		fields: fields,
+		layout: layout,
+		help:   help,
+		keymap: getFormConfigKeyMap(),
+	}
+}
+
+func getFormConfigKeyMap() formConfigKeymap {
+	return formConfigKeymap{
+		up:       newBinding(FormConfigKeys, "up", "up"),
+		down:     newBinding(FormConfigKeys, "down", "down"),
+		moveUp:   newBinding(FormConfigKeys, "move_up", "move up"),
+		moveDown: newBinding(FormConfigKeys, "move_down", "move down"),
+		hide:     newBinding(FormConfigKeys, "hide", "show/hide"),
+		required: newBinding(FormConfigKeys, "required", "required"),
+		save:     newBinding(FormConfigKeys, "save", "save"),
+		quit:     newBinding(FormConfigKeys, "quit", "quit"),
+	}
+}
+
+// returns the layout with the order of all fields, labels and placeholders are kept
+func (m formConfigModel) toFormConfig() settings.FormConfig {
+	config := settings.FormConfig{Fields: make(map[string]*settings.FieldConfig)}
+
+	for name, field := range m.layout.Fields {
+		if field != nil {
+			copied := *field
+			config.Fields[name] = &copied
+		}
+	}
+
+	for _, field := range m.fields {
+		config.Order = append(config.Order, field.title)
+
+		fieldConfig, ok := config.Fields[field.title]
+		if !ok {
+			fieldConfig = &settings.FieldConfig{}
+		}
+		fieldConfig.Hidden = field.hidden
+		fieldConfig.Required = field.required
+
+		if fieldConfig.IsZero() {
+			delete(config.Fields, field.title)
+		} else {
+			config.Fields[field.title] = fieldConfig
+		}
+	}
+
+	return config
+}
+
+func (m formConfigModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m formConfigModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(keyMsg, m.keymap.save):
+		m.saved = true
+		return m, tea.Quit
+	case key.Matches(keyMsg, m.keymap.quit):
+		m.quitting = true
+		return m, tea.Quit
+	}
+
+	if len(m.fields) == 0 {
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(keyMsg, m.keymap.up):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case key.Matches(keyMsg, m.keymap.down):
+		if m.cursor < len(m.fields)-1 {
+			m.cursor++
+		}
+	case key.Matches(keyMsg, m.keymap.moveUp):
+		if m.cursor > 0 {
+			m.fields[m.cursor], m.fields[m.cursor-1] = m.fields[m.cursor-1], m.fields[m.cursor]
+			m.cursor--
+		}
+	case key.Matches(keyMsg, m.keymap.moveDown):
+		if m.cursor < len(m.fields)-1 {
+			m.fields[m.cursor], m.fields[m.cursor+1] = m.fields[m.cursor+1], m.fields[m.cursor]
+			m.cursor++
+		}
+	// a hidden field can't be filled in, so it can't be required as well
+	case key.Matches(keyMsg, m.keymap.hide):
+		field := &m.fields[m.cursor]
+		field.hidden, field.required = !field.hidden, false
+	case key.Matches(keyMsg, m.keymap.required):
+		field := &m.fields[m.cursor]
+		field.hidden, field.required = false, !field.required
+	}
+
+	return m, nil
+}
+
+func (m formConfigModel) View() string {
+	if m.saved || m.quitting {
+		return ""
+	}
+
+	var view strings.Builder
+	view.WriteString(fmt.Sprintf("\n%s\n\n", inputStyle.Render("Configure the form fields:")))
+
+	if len(m.fields) == 0 {
+		view.WriteString(errorStyle.Render("The database has no properties supported by the form.") + "\n")
+	}
+
+	for i, field := range m.fields {
+		visible := "[x]"
+		if field.hidden {
+			visible = "[ ]"
+		}
+		required := ""
+		if field.required {
+			required = "required"
+		}
+
+		line := fmt.Sprintf("%s %-25s %-15s %s", visible, field.title, field.propType, required)
+		switch {
+		case i == m.cursor:
+			line = selectedFieldStyle.Render("> " + line)
+		case field.hidden:
+			line = hiddenFieldStyle.Render("  " + line)
+		default:
+			line = "  " + line
+		}
+		view.WriteString(fmt.Sprintf("  %s\n", line))
+	}
+
+	return fmt.Sprintf("%s\n%s\n\n", view.String(), m.helpView())
+}
+
+func (m formConfigModel) helpView() string {
+	return m.help.ShortHelpView([]key.Binding{
+		m.keymap.up,
+		m.keymap.down,
+		m.keymap.moveUp,
+		m.keymap.moveDown,
+		m.keymap.hide,
+		m.keymap.required,
+		m.keymap.save,
+		m.keymap.quit,
+	})
+}
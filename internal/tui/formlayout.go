@@ -0,0 +1,136 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	labelWidth    = 15
+	minInputWidth = 20
+	// terminals at least this wide show the props in two columns
+	wideLayoutWidth  = 120
+	minContentHeight = 5
+	// lines around the props and the content: scroll hints, the content title,
+	// the error, the help and the margins
+	formChromeHeight = 9
+)
+
+// fits the props and the content to the terminal, the content gets the space left by the props
+func (m *formModel) resize() {
+	m.columns = 1
+	if m.width >= wideLayoutWidth && len(m.props) > 1 {
+		m.columns = 2
+	}
+
+	// the label has a margin of 2, the input a prompt of 2
+	inputWidth := m.columnWidth() - labelWidth - 4
+	if inputWidth < minInputWidth {
+		inputWidth = minInputWidth
+	}
+	for i := range m.props {
+		m.props[i].model.Width = inputWidth
+	}
+
+	available := m.height - formChromeHeight
+	m.propsHeight = m.propRows()
+	contentHeight := available - m.propsHeight
+	if contentHeight < minContentHeight {
+		contentHeight = minContentHeight
+		m.propsHeight = available - minContentHeight
+		if m.propsHeight < 1 {
+			m.propsHeight = 1
+		}
+	}
+
+	m.help.Width = m.width
+	m.contentHeight = contentHeight
+	m.resizeBlocks()
+
+	m.scrollToFocused()
+}
+
+func (m formModel) columnWidth() int {
+	return (m.width - 2) / m.columns
+}
+
+func (m formModel) propRows() int {
+	return (len(m.props) + m.columns - 1) / m.columns
+}
+
+// scrolls the props so the focused one is visible
+func (m *formModel) scrollToFocused() {
+	if m.focusOnProps {
+		row := m.focusedProp / m.columns
+		if row < m.propsOffset {
+			m.propsOffset = row
+		}
+		if row >= m.propsOffset+m.propsHeight {
+			m.propsOffset = row - m.propsHeight + 1
+		}
+	}
+
+	if maxOffset := m.propRows() - m.propsHeight; m.propsOffset > maxOffset {
+		m.propsOffset = maxOffset
+	}
+	if m.propsOffset < 0 {
+		m.propsOffset = 0
+	}
+}
+
+func (m formModel) propView(prop PropInput) string {
+	label := prop.label
+	if prop.field != nil && prop.field.Required {
+		label += "*"
+	}
+	return fmt.Sprintf("%s%s%s", inputStyle.Width(labelWidth).Render(truncate(label, labelWidth-1)), prop.model.View(), getElemErrMsg(prop))
+}
+
+// renders the visible rows of props with the scroll hints above and below,
+// all props in a single column until the terminal size is known
+func (m formModel) propsView() string {
+	if m.width == 0 {
+		var rows []string
+		for _, prop := range m.props {
+			rows = append(rows, m.propView(prop))
+		}
+		return lipgloss.JoinVertical(lipgloss.Left, "", lipgloss.JoinVertical(lipgloss.Left, rows...), "")
+	}
+
+	cell := lipgloss.NewStyle().Width(m.columnWidth()).MaxWidth(m.columnWidth())
+
+	rows := make([]string, 0, m.propsHeight)
+	for row := m.propsOffset; row < m.propsOffset+m.propsHeight && row < m.propRows(); row++ {
+		var cells []string
+		for col := 0; col < m.columns; col++ {
+			if i := row*m.columns + col; i < len(m.props) {
+				cells = append(cells, cell.Render(m.propView(m.props[i])))
+			}
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, cells...))
+	}
+
+	above := ""
+	if m.propsOffset > 0 {
+		above = scrollHintStyle.Render(fmt.Sprintf("↑ %d more", m.propsOffset*m.columns))
+	}
+	below := ""
+	if hidden := len(m.props) - (m.propsOffset+m.propsHeight)*m.columns; hidden > 0 {
+		below = scrollHintStyle.Render(fmt.Sprintf("↓ %d more", hidden))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, above, lipgloss.JoinVertical(lipgloss.Left, rows...), below)
+}
+
+// shortens the string to the width with an ellipsis, empty when there is no room at all
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-1]) + "…"
+}
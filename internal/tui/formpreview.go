@@ -0,0 +1,141 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jomei/notionapi"
+)
+
+// terminals at least this wide show the preview beside the blocks, narrower ones below them
+const sidePreviewWidth = 100
+
+// the colored preview styles are set by the theme
+var (
+	previewStyle        = lipgloss.NewStyle().MarginLeft(2)
+	previewHeadingStyle = lipgloss.NewStyle().Bold(true)
+)
+
+func (m formModel) previewBeside() bool {
+	return m.width >= sidePreviewWidth
+}
+
+// returns the width and height of the blocks, what's left by the preview
+func (m formModel) blocksSize() (int, int) {
+	width, height := m.width-2, m.contentHeight
+	if !m.showPreview {
+		return width, height
+	}
+	if m.previewBeside() {
+		return width / 2, height
+	}
+	return width, height - m.previewHeight()
+}
+
+// height of the preview shown below the blocks, including its title
+func (m formModel) previewHeight() int {
+	return m.contentHeight / 2
+}
+
+func (m formModel) contentView() string {
+	blocks := m.blocksView()
+	if !m.showPreview {
+		return blocks
+	}
+	if m.width == 0 {
+		return blocks + m.previewView(50, 0)
+	}
+
+	blocksWidth, _ := m.blocksSize()
+	if m.previewBeside() {
+		// the blocks keep their width, so the preview doesn't jump while typing
+		blocks = lipgloss.NewStyle().Width(blocksWidth + 2).Render(blocks)
+		return lipgloss.JoinHorizontal(lipgloss.Top, blocks, m.previewView(m.width-blocksWidth-4, m.contentHeight)) + "\n"
+	}
+	return blocks + m.previewView(m.width-4, m.previewHeight())
+}
+
+// renders the content as it will look in Notion, from the same blocks that are sent on save,
+// cut off at the given height unless it's zero
+func (m formModel) previewView(width, height int) string {
+	var lines []string
+	number := 0
+	for _, block := range m.contentBlocks() {
+		// numbered lists restart after any other block, like in Notion
+		if block.GetType() == notionapi.BlockTypeNumberedListItem {
+			number++
+		} else {
+			number = 0
+		}
+		lines = append(lines, strings.Split(renderPreviewBlock(block, width, number), "\n")...)
+	}
+	if len(lines) == 0 {
+		lines = []string{previewMutedStyle.Render("Nothing to preview")}
+	}
+
+	// the title takes a line
+	if height > 1 && len(lines) > height-1 {
+		lines = append(lines[:height-2], previewMutedStyle.Render("…"))
+	}
+
+	title := inputStyle.Render("Preview")
+	return fmt.Sprintf("%s\n%s\n", title, previewStyle.Width(width).Render(strings.Join(lines, "\n")))
+}
+
+func renderPreviewBlock(block notionapi.Block, width int, number int) string {
+	text := lipgloss.NewStyle().Width(width)
+
+	switch b := block.(type) {
+	case notionapi.ParagraphBlock:
+		return text.Render(plainText(b.Paragraph.RichText))
+	case notionapi.Heading1Block:
+		return previewHeadingStyle.Copy().Underline(true).Width(width).Render(strings.ToUpper(plainText(b.Heading1.RichText)))
+	case notionapi.Heading2Block:
+		return previewHeadingStyle.Copy().Underline(true).Width(width).Render(plainText(b.Heading2.RichText))
+	case notionapi.Heading3Block:
+		return previewHeadingStyle.Copy().Width(width).Render(plainText(b.Heading3.RichText))
+	case notionapi.BulletedListItemBlock:
+		return listItem("• ", plainText(b.BulletedListItem.RichText), width)
+	case notionapi.NumberedListItemBlock:
+		return listItem(fmt.Sprintf("%d. ", number), plainText(b.NumberedListItem.RichText), width)
+	case notionapi.ToDoBlock:
+		box := "☐ "
+		if b.ToDo.Checked {
+			box = "☑ "
+		}
+		return listItem(box, plainText(b.ToDo.RichText), width)
+	case notionapi.CodeBlock:
+		language := previewMutedStyle.Render(b.Code.Language)
+		return previewCodeStyle.Width(width - 2).Render(language + "\n" + plainText(b.Code.RichText))
+	case notionapi.QuoteBlock:
+		return previewQuoteStyle.Width(width - 2).Render(plainText(b.Quote.RichText))
+	case notionapi.CalloutBlock:
+		icon := ""
+		if b.Callout.Icon != nil && b.Callout.Icon.Emoji != nil {
+			icon = string(*b.Callout.Icon.Emoji) + " "
+		}
+		return previewCalloutStyle.Width(width - 2).Render(icon + plainText(b.Callout.RichText))
+	case notionapi.DividerBlock:
+		return previewMutedStyle.Render(strings.Repeat("─", width))
+	}
+	return previewMutedStyle.Render(fmt.Sprintf("[%s]", block.GetType()))
+}
+
+// renders the item with its marker, wrapped lines are indented under the text
+func listItem(marker, content string, width int) string {
+	markerWidth := lipgloss.Width(marker)
+	return lipgloss.JoinHorizontal(lipgloss.Top, marker, lipgloss.NewStyle().Width(width-markerWidth).Render(content))
+}
+
+func plainText(richText []notionapi.RichText) string {
+	var text strings.Builder
+	for _, rt := range richText {
+		if rt.Text != nil {
+			text.WriteString(rt.Text.Content)
+		} else {
+			text.WriteString(rt.PlainText)
+		}
+	}
+	return text.String()
+}
@@ -0,0 +1,254 @@
+package tui
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ChmaraX/notidb/internal/settings"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+)
+
+const (
+	DefaultKeyPreset = "default"
+	VimKeyPreset     = "vim"
+	EmacsKeyPreset   = "emacs"
+)
+
+// sections of the key bindings, one per TUI
+const (
+	FormKeys       = "form"
+	FormConfigKeys = "form_config"
+	ListKeys       = "list"
+)
+
+// keys of an action, the first one is shown in the help
+type keyBindings map[string][]string
+
+// the form keys don't shadow the editing keys of the text inputs, see checkConflicts
+var defaultKeys = map[string]keyBindings{
+	FormKeys: {
+		"save":            {"ctrl+s"},
+		"edit":            {"alt+e"},
+		"preview":         {"ctrl+r"},
+		"add_block":       {"alt+o"},
+		"block_type":      {"alt+t"},
+		"move_block_up":   {"alt+up", "ctrl+up"},
+		"move_block_down": {"alt+down", "ctrl+down"},
+		"remove_block":    {"ctrl+x"},
+		"next":            {"tab"},
+		"prev":            {"shift+tab"},
+		"quit":            {"ctrl+c"},
+	},
+	FormConfigKeys: {
+		"up":        {"up", "k"},
+		"down":      {"down", "j"},
+		"move_up":   {"shift+up", "K"},
+		"move_down": {"shift+down", "J"},
+		"hide":      {" "},
+		"required":  {"r"},
+		"save":      {"enter", "ctrl+s"},
+		"quit":      {"esc", "q", "ctrl+c"},
+	},
+	ListKeys: {
+		"up":         {"up", "k"},
+		"down":       {"down", "j"},
+		"select":     {"enter"},
+		"toggle_ids": {"c"},
+		"sort":       {"s"},
+		"schema":     {"p"},
+		"quit":       {"q", "ctrl+c"},
+	},
+}
+
+// differences of the presets from the default keys
+var keyPresets = map[string]map[string]keyBindings{
+	DefaultKeyPreset: {},
+	VimKeyPreset: {
+		FormKeys: {
+			"add_block":       {"ctrl+o"},
+			"move_block_up":   {"alt+k", "alt+up"},
+			"move_block_down": {"alt+j", "alt+down"},
+			"quit":            {"ctrl+c", "ctrl+q"},
+		},
+		FormConfigKeys: {
+			"quit": {"q", "esc", "ctrl+c"},
+		},
+	},
+	EmacsKeyPreset: {
+		FormKeys: {
+			"preview":         {"alt+r"},
+			"move_block_up":   {"alt+up", "alt+p"},
+			"move_block_down": {"alt+down", "alt+n"},
+			"remove_block":    {"alt+k"},
+			"quit":            {"ctrl+g", "ctrl+c"},
+		},
+		FormConfigKeys: {
+			"up":        {"up", "ctrl+p"},
+			"down":      {"down", "ctrl+n"},
+			"move_up":   {"shift+up", "alt+p"},
+			"move_down": {"shift+down", "alt+n"},
+			"quit":      {"ctrl+g", "esc", "ctrl+c"},
+		},
+		ListKeys: {
+			"up":   {"up", "ctrl+p"},
+			"down": {"down", "ctrl+n"},
+			"quit": {"ctrl+g", "q", "ctrl+c"},
+		},
+	},
+}
+
+// key bindings in use, set by SetKeys
+var keys = resolvePreset(DefaultKeyPreset)
+
+// returns the names of the presets
+func KeyPresets() []string {
+	var names []string
+	for name := range keyPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// returns the actions of the section with their keys, in alphabetical order
+func KeyBindings(section string) [][2]string {
+	var bindings [][2]string
+	for _, action := range sortedActions(keys[section]) {
+		bindings = append(bindings, [2]string{action, strings.Join(keys[section][action], ", ")})
+	}
+	return bindings
+}
+
+// sets the key bindings of all TUI components from the preset and the keys of the config
+func SetKeys(config settings.KeysConfig) error {
+	preset := config.Preset
+	if preset == "" {
+		preset = DefaultKeyPreset
+	}
+	if _, ok := keyPresets[preset]; !ok {
+		return fmt.Errorf("unknown key preset %q, available: %v", preset, KeyPresets())
+	}
+
+	resolved := resolvePreset(preset)
+	overrides := map[string]map[string][]string{
+		FormKeys:       config.Form,
+		FormConfigKeys: config.FormConfig,
+		ListKeys:       config.List,
+	}
+	for section, bindings := range overrides {
+		for action, actionKeys := range bindings {
+			if _, ok := resolved[section][action]; !ok {
+				return fmt.Errorf("keys.%s: unknown action %q, available: %v", section, action, sortedActions(resolved[section]))
+			}
+			if len(actionKeys) == 0 {
+				return fmt.Errorf("keys.%s.%s: no keys given", section, action)
+			}
+			resolved[section][action] = actionKeys
+		}
+		if err := checkConflicts(section, resolved[section]); err != nil {
+			return err
+		}
+	}
+
+	keys = resolved
+	return nil
+}
+
+// returns a copy of the default keys with the preset applied
+func resolvePreset(preset string) map[string]keyBindings {
+	resolved := make(map[string]keyBindings)
+	for section, bindings := range defaultKeys {
+		resolved[section] = make(keyBindings)
+		for action, actionKeys := range bindings {
+			resolved[section][action] = actionKeys
+		}
+		for action, actionKeys := range keyPresets[preset][section] {
+			resolved[section][action] = actionKeys
+		}
+	}
+	return resolved
+}
+
+// a key can be bound to a single action, the form keys can't take the editing
+// keys of the text inputs either, as those would never reach the inputs
+func checkConflicts(section string, bindings keyBindings) error {
+	seen := make(map[string]string)
+	for _, action := range sortedActions(bindings) {
+		for _, k := range bindings[action] {
+			if other, ok := seen[k]; ok {
+				return fmt.Errorf("keys.%s: %q is bound to both %s and %s", section, k, other, action)
+			}
+			if section == FormKeys && inputKeys()[k] {
+				return fmt.Errorf("keys.%s.%s: %q is used for editing the text", section, action, k)
+			}
+			seen[k] = action
+		}
+	}
+	return nil
+}
+
+// returns the editing keys of the text inputs and the text area of the form
+func inputKeys() map[string]bool {
+	used := make(map[string]bool)
+	for _, keymap := range []interface{}{textinput.DefaultKeyMap, textarea.DefaultKeyMap} {
+		fields := reflect.ValueOf(keymap)
+		for i := 0; i < fields.NumField(); i++ {
+			binding, ok := fields.Field(i).Interface().(key.Binding)
+			if !ok {
+				continue
+			}
+			for _, k := range binding.Keys() {
+				used[k] = true
+			}
+		}
+	}
+	return used
+}
+
+func sortedActions(bindings keyBindings) []string {
+	var actions []string
+	for action := range bindings {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	return actions
+}
+
+// returns the binding of the action with its first key in the help
+func newBinding(section, action, desc string) key.Binding {
+	actionKeys := keys[section][action]
+	return key.NewBinding(
+		key.WithKeys(actionKeys...),
+		key.WithHelp(helpKey(actionKeys[0]), desc),
+	)
+}
+
+// returns a binding shown as a single help entry for a pair of actions, like moving up and down
+func newPairBinding(section, first, second, desc string) key.Binding {
+	binding := newBinding(section, first, desc)
+	binding.SetHelp(fmt.Sprintf("%s/%s", helpKey(keys[section][first][0]), helpKey(keys[section][second][0])), desc)
+	return binding
+}
+
+var keySymbols = map[string]string{
+	"up":    "↑",
+	"down":  "↓",
+	"left":  "←",
+	"right": "→",
+	" ":     "space",
+}
+
+// returns the key as shown in the help, like <alt+↑>
+func helpKey(k string) string {
+	parts := strings.Split(k, "+")
+	for i, part := range parts {
+		if symbol, ok := keySymbols[part]; ok {
+			parts[i] = symbol
+		}
+	}
+	return "<" + strings.Join(parts, "+") + ">"
+}
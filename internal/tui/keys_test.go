@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ChmaraX/notidb/internal/settings"
+)
+
+func TestSetKeys(t *testing.T) {
+	t.Cleanup(func() { _ = SetKeys(settings.KeysConfig{}) })
+
+	tests := []struct {
+		name    string
+		config  settings.KeysConfig
+		wantErr string
+	}{
+		{name: "default preset", config: settings.KeysConfig{}},
+		{name: "vim preset", config: settings.KeysConfig{Preset: VimKeyPreset}},
+		{name: "emacs preset", config: settings.KeysConfig{Preset: EmacsKeyPreset}},
+		{
+			name:   "override",
+			config: settings.KeysConfig{Form: map[string][]string{"save": {"ctrl+y"}}},
+		},
+		{
+			name:    "unknown preset",
+			config:  settings.KeysConfig{Preset: "nano"},
+			wantErr: "unknown key preset",
+		},
+		{
+			name:    "unknown action",
+			config:  settings.KeysConfig{List: map[string][]string{"jump": {"g"}}},
+			wantErr: "unknown action",
+		},
+		{
+			name:    "no keys",
+			config:  settings.KeysConfig{List: map[string][]string{"toggle_ids": {}}},
+			wantErr: "no keys given",
+		},
+		{
+			name:    "key bound twice",
+			config:  settings.KeysConfig{List: map[string][]string{"select": {"c"}}},
+			wantErr: "is bound to both",
+		},
+		{
+			name:    "key bound twice by the preset and the config",
+			config:  settings.KeysConfig{Preset: EmacsKeyPreset, Form: map[string][]string{"next": {"alt+n"}}},
+			wantErr: "is bound to both",
+		},
+		{
+			name:    "text input key",
+			config:  settings.KeysConfig{Form: map[string][]string{"edit": {"ctrl+e"}}},
+			wantErr: "is used for editing the text",
+		},
+		{
+			name:    "text area key",
+			config:  settings.KeysConfig{Form: map[string][]string{"block_type": {"ctrl+t"}}},
+			wantErr: "is used for editing the text",
+		},
+		{
+			name:   "text key outside of the form",
+			config: settings.KeysConfig{FormConfig: map[string][]string{"hide": {"ctrl+e"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := SetKeys(tt.config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("SetKeys() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("SetKeys() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetKeysKeepsKeysOnError(t *testing.T) {
+	t.Cleanup(func() { _ = SetKeys(settings.KeysConfig{}) })
+
+	if err := SetKeys(settings.KeysConfig{Form: map[string][]string{"save": {"ctrl+y"}}}); err != nil {
+		t.Fatalf("SetKeys() error = %v", err)
+	}
+	if err := SetKeys(settings.KeysConfig{Form: map[string][]string{"save": {"ctrl+e"}}}); err == nil {
+		t.Fatalf("SetKeys() error = nil, want a conflict")
+	}
+	if got := keys[FormKeys]["save"]; len(got) != 1 || got[0] != "ctrl+y" {
+		t.Errorf("save keys = %v, want the previous [ctrl+y]", got)
+	}
+}
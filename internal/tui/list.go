@@ -4,40 +4,62 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/ChmaraX/notidb/internal/notion"
 	"github.com/ChmaraX/notidb/internal/settings"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jomei/notionapi"
 )
 
-const listHeight = 14
+const listHeight = 20
 const listWidth = 60
 
+const (
+	// terminals at least this wide show the schema of the selected database beside the list
+	schemaPanelMinWidth = 100
+	schemaPanelWidth    = 40
+)
+
 var (
-	titleStyle        = lipgloss.NewStyle().MarginLeft(2)
-	itemStyle         = lipgloss.NewStyle().PaddingLeft(4)
-	selectedItemStyle = lipgloss.NewStyle().PaddingLeft(2).Foreground(lipgloss.Color("170"))
-	paginationStyle   = list.DefaultStyles().PaginationStyle.PaddingLeft(4)
-	helpStyle         = list.DefaultStyles().HelpStyle.PaddingLeft(4).PaddingBottom(1)
-	quitTextStyle     = lipgloss.NewStyle().Margin(1, 0, 2, 1)
-	checkMark         = lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Render("✓")
+	titleStyle      = lipgloss.NewStyle().MarginLeft(2)
+	itemStyle       = lipgloss.NewStyle().PaddingLeft(4)
+	paginationStyle = list.DefaultStyles().PaginationStyle.PaddingLeft(4)
+	helpStyle       = list.DefaultStyles().HelpStyle.PaddingLeft(4).PaddingBottom(1)
+	quitTextStyle   = lipgloss.NewStyle().Margin(1, 0, 2, 1)
 )
 
 type item struct {
 	id, title string
 	def       bool
+	icon      string
+	// titles of the pages above the database
+	path   string
+	edited time.Time
+	schema notionapi.PropertyConfigs
 }
 
 var showIds = false
 
-func (i item) FilterValue() string { return i.title }
+func (i item) FilterValue() string { return i.title + " " + i.path }
+
+// path, last edit and number of properties shown below the title
+func (i item) details() string {
+	details := []string{fmt.Sprintf("edited %s", formatAge(i.edited)), fmt.Sprintf("%d properties", len(i.schema))}
+	if i.path != "" {
+		details = append([]string{i.path}, details...)
+	}
+	return strings.Join(details, " · ")
+}
 
 type itemDelegate struct{}
 
-func (d itemDelegate) Height() int                             { return 1 }
+func (d itemDelegate) Height() int                             { return 2 }
 func (d itemDelegate) Spacing() int                            { return 0 }
 func (d itemDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
 func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
@@ -47,7 +69,10 @@ func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list
 	}
 
 	var builder strings.Builder
-	builder.WriteString(fmt.Sprintf("%d. %s", index+1, i.title))
+	if i.icon != "" {
+		builder.WriteString(i.icon + " ")
+	}
+	builder.WriteString(i.title)
 
 	if i.def {
 		builder.WriteString(" [default]")
@@ -57,6 +82,25 @@ func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list
 		builder.WriteString(fmt.Sprintf(" (%s)", i.id))
 	}
 
+	fmt.Fprint(w, renderListItem(m, index, builder.String(), i.details()))
+}
+
+// renders the numbered title with the details below it
+func renderListItem(m list.Model, index int, title, details string) string {
+	number := fmt.Sprintf("%d. ", index+1)
+
+	// the details line up with the title, after the marker of the selected item,
+	// very narrow terminals leave no room at all
+	width := m.Width() - 6
+	if width < 0 {
+		width = 0
+	}
+	detailsWidth := width - len(number)
+	if detailsWidth < 0 {
+		detailsWidth = 0
+	}
+	details = scrollHintStyle.Copy().MarginLeft(len(number)).Render(truncate(details, detailsWidth))
+
 	var renderFn func(...string) string
 	if index == m.Index() {
 		renderFn = func(s ...string) string {
@@ -66,43 +110,165 @@ func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list
 		renderFn = itemStyle.Render
 	}
 
-	fmt.Fprint(w, renderFn(builder.String()))
+	return renderFn(truncate(number+title, width)) + "\n" + itemStyle.Render(details)
 }
 
 type dbListModel struct {
-	list        list.Model
-	choice      string
-	quitting    bool
+	list     list.Model
+	choice   string
+	quitting bool
+	err      error
+	opts     DbListOptions
+	keymap   dbListKeymap
+	// terminal width, zero until the first tea.WindowSizeMsg
+	width      int
+	byRecency  bool
+	showSchema bool
+	// true until all pages of the databases are loaded
+	loading     bool
 	defaultDbId string
+	// databases shown before their paths are known, resolved by Init
+	unresolved []notionapi.Database
 }
 
+type dbListKeymap struct {
+	selectDb     key.Binding
+	toggleIds    key.Binding
+	toggleSort   key.Binding
+	toggleSchema key.Binding
+	quit         key.Binding
+}
+
+func getDbListKeyMap() dbListKeymap {
+	return dbListKeymap{
+		selectDb:     newBinding(ListKeys, "select", "select"),
+		toggleIds:    newBinding(ListKeys, "toggle_ids", "show IDs"),
+		toggleSort:   newBinding(ListKeys, "sort", "sort"),
+		toggleSchema: newBinding(ListKeys, "schema", "schema"),
+		quit:         newBinding(ListKeys, "quit", "quit"),
+	}
+}
+
+// DbListOptions describe what happens when a database is picked from the list
+type DbListOptions struct {
+	Title      string
+	SuccessMsg string
+	QuitMsg    string
+	OnSelect   func(dbId string) error
+	// titles of the pages above the databases by database ID, see notion.ParentPaths
+	Paths map[string]string
+	// resolves the paths of the databases missing from Paths while the list is shown,
+	// the databases left out are shown without a path, nil when all paths are known
+	ResolvePaths func(dbs []notionapi.Database) map[string]string
+	// loads the next page of databases while the list is shown, nil when all are loaded
+	LoadMore func() (DbPage, error)
+}
+
+// DbPage is a page of databases loaded after the list is shown
+type DbPage struct {
+	Dbs     []notionapi.Database
+	HasMore bool
+}
+
+type dbPageMsg struct {
+	page DbPage
+	err  error
+}
+
+// paths of the databases by ID resolved while the list is shown
+type dbPathsMsg map[string]string
+
 func (m dbListModel) Init() tea.Cmd {
-	return nil
+	cmd := m.resolvePaths(m.unresolved)
+	if m.loading {
+		return tea.Batch(m.list.StartSpinner(), m.loadMore(), cmd)
+	}
+	return cmd
+}
+
+func (m dbListModel) resolvePaths(dbs []notionapi.Database) tea.Cmd {
+	if m.opts.ResolvePaths == nil || len(dbs) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		return dbPathsMsg(m.opts.ResolvePaths(dbs))
+	}
+}
+
+func (m dbListModel) loadMore() tea.Cmd {
+	return func() tea.Msg {
+		page, err := m.opts.LoadMore()
+		return dbPageMsg{page: page, err: err}
+	}
 }
 
 func (m dbListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		m.list.SetWidth(msg.Width)
+		m.width = msg.Width
+		m.list.SetHeight(msg.Height - 1)
+		m.resize()
 		return m, nil
 
+	case dbPageMsg:
+		if msg.err != nil {
+			m.loading = false
+			m.list.StopSpinner()
+			return m, m.list.NewStatusMessage(errorStyle.Render(fmt.Sprintf("Error: %v", msg.err)))
+		}
+
+		cmd := tea.Batch(m.addPage(msg.page), m.resolvePaths(msg.page.Dbs))
+		if m.loading {
+			return m, tea.Batch(cmd, m.loadMore())
+		}
+		m.list.StopSpinner()
+		if m.defaultDbId != "" && m.defaultDbId != settings.NoDefaultDatabaseId && !m.hasDefault() {
+			return m, tea.Batch(cmd, m.list.NewStatusMessage(errorStyle.Render(fmt.Sprintf("The default database %s was not found", m.defaultDbId))))
+		}
+		return m, cmd
+
+	case dbPathsMsg:
+		return m, m.setPaths(msg)
+
 	case tea.KeyMsg:
-		switch keypress := msg.String(); keypress {
-		case "ctrl+c", "q":
+		// the keys are typed into the filter while filtering, and an applied filter
+		// is cleared before its key can quit
+		if m.list.FilterState() == list.Filtering {
+			break
+		}
+		if m.list.FilterState() == list.FilterApplied && key.Matches(msg, m.list.KeyMap.ClearFilter) {
+			break
+		}
+
+		switch {
+		case key.Matches(msg, m.keymap.quit):
 			m.quitting = true
 			return m, tea.Quit
 
-		case "enter":
+		case key.Matches(msg, m.keymap.selectDb):
 			i, ok := m.list.SelectedItem().(item)
 			if ok {
+				if err := m.opts.OnSelect(i.id); err != nil {
+					m.err = err
+					return m, tea.Quit
+				}
 				m.choice = i.title
-				settings.SetDefaultDatabase(i.id)
 			}
 			return m, tea.Quit
 
-		case "c":
+		case key.Matches(msg, m.keymap.toggleIds):
 			showIds = !showIds
 			return m, nil
+
+		case key.Matches(msg, m.keymap.toggleSort):
+			m.byRecency = !m.byRecency
+			m.list.Title = m.title()
+			return m, m.list.SetItems(sortItems(m.list.Items(), m.byRecency))
+
+		case key.Matches(msg, m.keymap.toggleSchema):
+			m.showSchema = !m.showSchema
+			m.resize()
+			return m, nil
 		}
 
 	}
@@ -113,45 +279,272 @@ func (m dbListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m dbListModel) View() string {
+	if m.err != nil {
+		return quitTextStyle.Render(fmt.Sprintf("Error: %v", m.err))
+	}
 	if m.choice != "" {
-		highlightStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("170"))
-		return quitTextStyle.Render(fmt.Sprintf("%s Default database successfully set to: %s", checkMark, highlightStyle.Render(m.choice)))
+		return quitTextStyle.Render(fmt.Sprintf("%s %s: %s", checkMark, m.opts.SuccessMsg, highlightStyle.Render(m.choice)))
 	}
 	if m.quitting {
-		if m.defaultDbId == settings.NoDefaultDatabaseId {
-			return quitTextStyle.Render("No default database set.")
-		}
-		return quitTextStyle.Render("No changes made.")
+		return quitTextStyle.Render(m.opts.QuitMsg)
+	}
+	if m.schemaShown() {
+		return "\n" + lipgloss.JoinHorizontal(lipgloss.Top, m.list.View(), m.schemaView())
 	}
 	return "\n" + m.list.View()
 }
 
-func newDbListModel(dbs []notionapi.Database, defaultDbId string) *dbListModel {
+func (m dbListModel) schemaShown() bool {
+	return m.showSchema && m.width >= schemaPanelMinWidth
+}
+
+func (m *dbListModel) resize() {
+	if m.schemaShown() {
+		m.list.SetWidth(m.width - schemaPanelWidth)
+	} else {
+		m.list.SetWidth(m.width)
+	}
+}
+
+func (m dbListModel) title() string {
+	if m.byRecency {
+		return m.opts.Title + " (recently edited first)"
+	}
+	return m.opts.Title
+}
+
+// renders the properties of the selected database with their types and options
+func (m dbListModel) schemaView() string {
+	i, ok := m.list.SelectedItem().(item)
+	if !ok {
+		return ""
+	}
+
+	width := schemaPanelWidth - 4
+	names := schemaOrder(i.schema)
+	nameWidth := 0
+	for _, name := range names {
+		if n := len([]rune(name)); n > nameWidth {
+			nameWidth = n
+		}
+	}
+	if nameWidth > width/2 {
+		nameWidth = width / 2
+	}
+
+	lines := []string{inputStyle.Render(truncate(i.title, width)), ""}
+	for _, name := range names {
+		config := i.schema[name]
+		lines = append(lines, fmt.Sprintf("  %-*s %s", nameWidth, truncate(name, nameWidth), hiddenFieldStyle.Render(string(config.GetType()))))
+		if options := propertyOptions(config); len(options) > 0 {
+			lines = append(lines, scrollHintStyle.Copy().MarginLeft(4).Render(truncate(strings.Join(options, ", "), width-2)))
+		}
+	}
+
+	// the panel is as high as the list
+	if height := m.list.Height(); len(lines) > height && height > 1 {
+		lines = append(lines[:height-1], scrollHintStyle.Render("…"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// the title property first, then the other ones by name
+func schemaOrder(schema notionapi.PropertyConfigs) []string {
+	var names []string
+	for name := range schema {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(a, b int) bool {
+		aTitle := schema[names[a]].GetType() == notionapi.PropertyConfigTypeTitle
+		bTitle := schema[names[b]].GetType() == notionapi.PropertyConfigTypeTitle
+		if aTitle != bTitle {
+			return aTitle
+		}
+		return names[a] < names[b]
+	})
+	return names
+}
+
+// returns the option names of select, multi-select and status properties
+func propertyOptions(config notionapi.PropertyConfig) []string {
+	var options []notionapi.Option
+	switch c := config.(type) {
+	case *notionapi.SelectPropertyConfig:
+		options = c.Select.Options
+	case *notionapi.MultiSelectPropertyConfig:
+		options = c.MultiSelect.Options
+	case *notionapi.StatusPropertyConfig:
+		options = c.Status.Options
+	}
+
+	names := make([]string, len(options))
+	for i, option := range options {
+		names[i] = option.Name
+	}
+	return names
+}
+
+// sorts by title, or by the last edit with the most recent first
+func sortItems(items []list.Item, byRecency bool) []list.Item {
+	sorted := make([]list.Item, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		aItem, bItem := sorted[a].(item), sorted[b].(item)
+		if byRecency {
+			return aItem.edited.After(bItem.edited)
+		}
+		return strings.ToLower(aItem.title) < strings.ToLower(bItem.title)
+	})
+	return sorted
+}
+
+// returns the time like "3 hours ago", older times as a date
+func formatAge(t time.Time) string {
+	age := time.Since(t)
+	switch {
+	case t.IsZero():
+		return "at unknown time"
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return plural(int(age.Minutes()), "minute") + " ago"
+	case age < 24*time.Hour:
+		return plural(int(age.Hours()), "hour") + " ago"
+	case age < 30*24*time.Hour:
+		return plural(int(age.Hours()/24), "day") + " ago"
+	}
+	return t.Format("2 Jan 2006")
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+func databaseIcon(db notionapi.Database) string {
+	if db.Icon != nil && db.Icon.Emoji != nil {
+		return string(*db.Icon.Emoji)
+	}
+	return ""
+}
+
+func newItems(dbs []notionapi.Database, paths map[string]string, defaultDbId string) []list.Item {
 	items := make([]list.Item, len(dbs))
 	for i, db := range dbs {
-		items[i] = item{title: db.Title[0].PlainText, id: string(db.ID), def: string(db.ID) == defaultDbId}
+		title := notion.DatabaseTitle(db)
+		if strings.TrimSpace(title) == "" {
+			title = "Untitled"
+		}
+		items[i] = item{
+			id:     string(db.ID),
+			title:  title,
+			def:    string(db.ID) == defaultDbId,
+			icon:   databaseIcon(db),
+			path:   paths[string(db.ID)],
+			edited: db.LastEditedTime,
+			schema: db.Properties,
+		}
 	}
+	return items
+}
 
-	l := newListModel(items)
-	m := dbListModel{list: l, defaultDbId: defaultDbId}
+// adds the databases of the page to the list, the selected database stays selected
+func (m *dbListModel) addPage(page DbPage) tea.Cmd {
+	m.loading = page.HasMore
+
+	selected, _ := m.list.SelectedItem().(item)
+	// the paths of the page are resolved after it's shown
+	items := append(m.list.Items(), newItems(page.Dbs, nil, m.defaultDbId)...)
+	cmd := m.list.SetItems(sortItems(items, m.byRecency))
+
+	// the index of a filtered list is the one of the matching items
+	if m.list.FilterState() == list.Unfiltered {
+		for i, listItem := range m.list.Items() {
+			if listItem.(item).id == selected.id {
+				m.list.Select(i)
+				break
+			}
+		}
+	}
+	return cmd
+}
+
+// sets the resolved paths of the items, the order of the items doesn't depend on them
+func (m *dbListModel) setPaths(paths map[string]string) tea.Cmd {
+	var cmds []tea.Cmd
+	for i, listItem := range m.list.Items() {
+		it := listItem.(item)
+		if path, ok := paths[it.id]; ok && path != it.path {
+			it.path = path
+			cmds = append(cmds, m.list.SetItem(i, it))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m dbListModel) hasDefault() bool {
+	for _, listItem := range m.list.Items() {
+		if listItem.(item).def {
+			return true
+		}
+	}
+	return false
+}
+
+func newDbListModel(dbs []notionapi.Database, defaultDbId string, opts DbListOptions) *dbListModel {
+	keymap := getDbListKeyMap()
+	l := newListModel(sortItems(newItems(dbs, opts.Paths, defaultDbId), false), opts.Title)
+	l.KeyMap.Quit = keymap.quit
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{keymap.selectDb, keymap.toggleSort, keymap.toggleSchema, keymap.toggleIds}
+	}
+	m := dbListModel{list: l, opts: opts, keymap: keymap, showSchema: true, loading: opts.LoadMore != nil, defaultDbId: defaultDbId}
+	for _, db := range dbs {
+		if _, ok := opts.Paths[string(db.ID)]; !ok {
+			m.unresolved = append(m.unresolved, db)
+		}
+	}
 
 	return &m
 }
 
-func newListModel(items []list.Item) list.Model {
+func newListModel(items []list.Item, title string) list.Model {
 	l := list.New(items, itemDelegate{}, listWidth, listHeight)
-	l.Title = "Choose default database for operations:"
+	l.Title = title
 	l.SetShowStatusBar(true)
 	l.SetFilteringEnabled(true)
 	l.Styles.Title = titleStyle
 	l.Styles.PaginationStyle = paginationStyle
 	l.Styles.HelpStyle = helpStyle
+	l.KeyMap.CursorUp = newBinding(ListKeys, "up", "up")
+	l.KeyMap.CursorDown = newBinding(ListKeys, "down", "down")
 
 	return l
 }
 
-func InitDbListModel(dbs []notionapi.Database, defaultDbId string) {
-	m := newDbListModel(dbs, defaultDbId)
+func InitDbListModel(dbs []notionapi.Database, defaultDbId string, opts DbListOptions) {
+	m := newDbListModel(dbs, defaultDbId, opts)
+	if plain {
+		// the numbers of the databases change when more of them are added, so all are loaded first
+		unresolved := m.unresolved
+		for m.loading {
+			page, err := opts.LoadMore()
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			m.addPage(page)
+			unresolved = append(unresolved, page.Dbs...)
+		}
+		if opts.ResolvePaths != nil && len(unresolved) > 0 {
+			m.setPaths(opts.ResolvePaths(unresolved))
+		}
+		runPlainDbList(*m)
+		return
+	}
+	detectBackground()
 	if _, err := tea.NewProgram(m).Run(); err != nil {
 		fmt.Println("Error running program:", err)
 		os.Exit(1)
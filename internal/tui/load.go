@@ -47,6 +47,7 @@ type Response struct {
 func newLoadingModel(action string, funcs ...LoadingFunc) LoadingModel {
 	s := spinner.New()
 	s.Spinner = spinner.Points
+	s.Style = spinnerStyle
 
 	return LoadingModel{
 		spinner:    s,
@@ -110,6 +111,10 @@ func (m LoadingModel) View() string {
 
 func NewLoadingModel(action string, funcs ...LoadingFunc) LoadingModel {
 	m := newLoadingModel(action, funcs...)
+	if plain {
+		return runPlainLoading(m)
+	}
+	detectBackground()
 	model, err := tea.NewProgram(m).Run()
 
 	if err != nil {
@@ -0,0 +1,357 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ChmaraX/notidb/internal/notion"
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/term"
+)
+
+const PlainEnvVar = "NOTIDB_PLAIN"
+
+// replaces the full-screen programs with log lines and line-based prompts
+var plain bool
+
+// shared by all prompts, so buffered input isn't lost between them when it's piped
+var stdin = bufio.NewReader(os.Stdin)
+
+// enables the plain mode when it's requested or when stdin or stdout isn't a terminal
+func SetPlain(requested bool) {
+	plain = requested || os.Getenv(PlainEnvVar) != "" ||
+		!term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// reads a line from stdin without the line break, io.EOF when the input ended
+func ReadLine() (string, error) {
+	line, err := stdin.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// prints the prompt and reads the answer
+func prompt(format string, a ...interface{}) (string, error) {
+	return fprompt(os.Stdout, format, a...)
+}
+
+// prints the prompt to w and reads the answer
+func fprompt(w io.Writer, format string, a ...interface{}) (string, error) {
+	fmt.Fprintf(w, format, a...)
+	answer, err := ReadLine()
+	return strings.TrimSpace(answer), err
+}
+
+// runs the functions like the loading model, with a log line instead of the spinner,
+// the log lines go to stderr so they don't mix with the output of the command
+func runPlainLoading(m LoadingModel) LoadingModel {
+	fmt.Fprintf(os.Stderr, "%s...\n", m.action)
+
+	msgs := make(chan tea.Msg, len(m.asyncFuncs))
+	for _, f := range m.asyncFuncs {
+		go func(f func() tea.Msg) {
+			msgs <- f()
+		}(f)
+	}
+
+	for len(m.Responses) < m.NumFuncs && m.err == nil {
+		model, _ := m.Update(<-msgs)
+		m = model.(LoadingModel)
+	}
+
+	fmt.Fprintln(os.Stderr, m.View())
+	return m
+}
+
+// asks for the props one by one and then for the content, the values in brackets are kept
+// when the answer is empty, the form is quit without saving when the input ends
+func runPlainForm(m formModel) formModel {
+	fmt.Println("Leave the answer empty to keep the value in brackets, enter - to clear it.")
+
+	for i := range m.props {
+		if !m.promptProp(&m.props[i]) {
+			return m
+		}
+		m.autosave()
+	}
+
+	body, ok := m.promptBody()
+	if !ok {
+		return m
+	}
+	if body != "" {
+		m.replaceBlocks(body)
+	}
+
+	if m.rawValues {
+		m.values = m.toFormValues()
+		m.saved = true
+		return m
+	}
+
+	// hidden props can't be fixed here, their errors are only reported
+	if err := m.validate(); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return m
+	}
+	entry, err := m.toDatabaseEntry()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return m
+	}
+
+	// kept until the entry is saved to Notion, so it can be resumed if that fails
+	m.autosave()
+	m.entry = entry
+	return m
+}
+
+// asks for the value until it's valid, returns false when the input ended
+func (m *formModel) promptProp(prop *PropInput) bool {
+	label := prop.label
+	if prop.field != nil && prop.field.Required {
+		label += "*"
+	}
+	hint := string(prop.propType)
+	if prop.model.Placeholder != "" {
+		hint = prop.model.Placeholder
+	}
+
+	for {
+		answer, err := prompt("%s (%s) [%s]: ", label, hint, prop.model.Value())
+		if err != nil {
+			fmt.Println()
+			return false
+		}
+
+		previous := prop.model.Value()
+		switch answer {
+		case "":
+		case "-":
+			prop.setValue("")
+		default:
+			prop.setValue(answer)
+		}
+
+		if err := prop.validate(); err != nil {
+			fmt.Printf("  %v\n", err)
+			prop.setValue(previous)
+			continue
+		}
+		return true
+	}
+}
+
+// reads the content until a line with a single dot, returns false when the input ended
+// before any content, an empty body keeps the current content
+func (m *formModel) promptBody() (string, bool) {
+	if body := m.bodyMarkdown(); body != "" {
+		fmt.Printf("Current content:\n%s\n", body)
+		fmt.Println("Content (Markdown), end with a line with a single dot, a dot right away keeps the current content:")
+	} else {
+		fmt.Println("Content (Markdown), end with a line with a single dot:")
+	}
+
+	var lines []string
+	for {
+		line, err := ReadLine()
+		if err != nil {
+			// the content can be ended by the end of the input too
+			return strings.Join(lines, "\n"), len(lines) > 0
+		}
+		if line == "." {
+			return strings.Join(lines, "\n"), true
+		}
+		lines = append(lines, line)
+	}
+}
+
+// lists the databases and asks for one by its number or part of its title
+func runPlainDbList(m dbListModel) {
+	items := make([]item, 0, len(m.list.Items()))
+	for _, listItem := range m.list.Items() {
+		if i, ok := listItem.(item); ok {
+			items = append(items, i)
+		}
+	}
+
+	fmt.Println(m.opts.Title)
+	printPlainItems(items, nil)
+
+	for {
+		answer, err := prompt("Select a database by number or title, empty to quit: ")
+		if err != nil || answer == "" {
+			if err != nil {
+				fmt.Println()
+			}
+			m.quitting = true
+			break
+		}
+
+		matches := matchTitles(itemTitles(items), answer)
+		if len(matches) != 1 {
+			if len(matches) == 0 {
+				fmt.Printf("No database matches %q\n", answer)
+			} else {
+				printPlainItems(items, matches)
+			}
+			continue
+		}
+
+		selected := items[matches[0]]
+		if err := m.opts.OnSelect(selected.id); err != nil {
+			m.err = err
+		} else {
+			m.choice = selected.title
+		}
+		break
+	}
+
+	fmt.Println(m.View())
+}
+
+func itemTitles(items []item) []string {
+	titles := make([]string, len(items))
+	for i, it := range items {
+		titles[i] = it.title
+	}
+	return titles
+}
+
+// returns the indexes of the titles matching the number or the title, an exact title wins
+func matchTitles(titles []string, answer string) []int {
+	if n, err := strconv.Atoi(answer); err == nil {
+		if n >= 1 && n <= len(titles) {
+			return []int{n - 1}
+		}
+		return nil
+	}
+
+	var matches []int
+	for i, title := range titles {
+		if strings.EqualFold(title, answer) {
+			return []int{i}
+		}
+		if strings.Contains(strings.ToLower(title), strings.ToLower(answer)) {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+// prints the items with their numbers, only the given ones unless nil
+func printPlainItems(items []item, only []int) {
+	if only == nil {
+		for i := range items {
+			only = append(only, i)
+		}
+	}
+	for _, i := range only {
+		line := fmt.Sprintf("%d. %s (%s)", i+1, items[i].title, items[i].id)
+		if items[i].def {
+			line += " [default]"
+		}
+		fmt.Printf("%s\n   %s\n", line, items[i].details())
+	}
+}
+
+// lists the results and asks for one by its number or part of its title
+func runPlainSearchList(m searchListModel) (notion.SearchResult, bool) {
+	var items []searchItem
+	for _, listItem := range m.list.Items() {
+		if i, ok := listItem.(searchItem); ok {
+			items = append(items, i)
+		}
+	}
+
+	titles := make([]string, len(items))
+	for i, it := range items {
+		titles[i] = it.title()
+	}
+
+	// only the chosen result goes to stdout
+	fmt.Fprintln(os.Stderr, m.opts.Title)
+	printPlainSearchItems(items, nil)
+
+	for {
+		answer, err := fprompt(os.Stderr, "Select a result by number or title, empty to quit: ")
+		if err != nil || answer == "" {
+			if err != nil {
+				fmt.Fprintln(os.Stderr)
+			}
+			return notion.SearchResult{}, false
+		}
+
+		matches := matchTitles(titles, answer)
+		if len(matches) == 1 {
+			return items[matches[0]].SearchResult, true
+		}
+		if len(matches) == 0 {
+			fmt.Fprintf(os.Stderr, "No result matches %q\n", answer)
+		} else {
+			printPlainSearchItems(items, matches)
+		}
+	}
+}
+
+// prints the results with their numbers, only the given ones unless nil
+func printPlainSearchItems(items []searchItem, only []int) {
+	if only == nil {
+		for i := range items {
+			only = append(only, i)
+		}
+	}
+	for _, i := range only {
+		fmt.Fprintf(os.Stderr, "%d. %s (%s)\n   %s · edited %s\n", i+1, items[i].title(), items[i].ID, items[i].Object, formatAge(items[i].LastEdited))
+	}
+}
+
+// asks for each field whether it's shown, hidden or required, the order is kept
+func runPlainFormConfig(m formConfigModel) formConfigModel {
+	if len(m.fields) == 0 {
+		fmt.Println("The database has no properties supported by the form.")
+		return m
+	}
+	fmt.Println("Set each field as shown, hidden or required, leave the answer empty to keep it as it is.")
+
+	for i := range m.fields {
+		field := &m.fields[i]
+		for {
+			current := "shown"
+			if field.hidden {
+				current = "hidden"
+			} else if field.required {
+				current = "required"
+			}
+
+			answer, err := prompt("%s (%s) [%s]: ", field.title, field.propType, current)
+			if err != nil {
+				fmt.Println()
+				return m
+			}
+
+			switch strings.ToLower(answer) {
+			case "":
+			case "s", "shown":
+				field.hidden, field.required = false, false
+			case "h", "hidden":
+				field.hidden, field.required = true, false
+			case "r", "required":
+				field.hidden, field.required = false, true
+			default:
+				fmt.Println("  must be shown, hidden or required")
+				continue
+			}
+			break
+		}
+	}
+
+	m.saved = true
+	return m
+}
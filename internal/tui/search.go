@@ -0,0 +1,193 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ChmaraX/notidb/internal/notion"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type searchItem struct {
+	notion.SearchResult
+}
+
+func (i searchItem) FilterValue() string { return i.title() }
+
+func (i searchItem) title() string {
+	if strings.TrimSpace(i.Title) == "" {
+		return "Untitled"
+	}
+	return i.Title
+}
+
+type searchDelegate struct{}
+
+func (d searchDelegate) Height() int                             { return 2 }
+func (d searchDelegate) Spacing() int                            { return 0 }
+func (d searchDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+func (d searchDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	i, ok := listItem.(searchItem)
+	if !ok {
+		return
+	}
+
+	title := i.title()
+	if i.Icon != "" {
+		title = i.Icon + " " + title
+	}
+	if showIds {
+		title += fmt.Sprintf(" (%s)", i.ID)
+	}
+
+	fmt.Fprint(w, renderListItem(m, index, title, fmt.Sprintf("%s · edited %s", i.Object, formatAge(i.LastEdited))))
+}
+
+// SearchListOptions describe the results shown in the search list
+type SearchListOptions struct {
+	Title string
+	// loads the next page of results while the list is shown, nil when all are loaded
+	LoadMore func() (results []notion.SearchResult, hasMore bool, err error)
+}
+
+type searchPageMsg struct {
+	results []notion.SearchResult
+	hasMore bool
+	err     error
+}
+
+type searchListModel struct {
+	list    list.Model
+	choice  *notion.SearchResult
+	opts    SearchListOptions
+	keymap  dbListKeymap
+	loading bool
+}
+
+func (m searchListModel) Init() tea.Cmd {
+	if m.loading {
+		return tea.Batch(m.list.StartSpinner(), m.loadMore())
+	}
+	return nil
+}
+
+func (m searchListModel) loadMore() tea.Cmd {
+	return func() tea.Msg {
+		results, hasMore, err := m.opts.LoadMore()
+		return searchPageMsg{results: results, hasMore: hasMore, err: err}
+	}
+}
+
+func (m searchListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.list.SetSize(msg.Width, msg.Height-1)
+		return m, nil
+
+	case searchPageMsg:
+		if msg.err != nil {
+			m.loading = false
+			m.list.StopSpinner()
+			return m, m.list.NewStatusMessage(errorStyle.Render(fmt.Sprintf("Error: %v", msg.err)))
+		}
+
+		cmd := m.addResults(msg.results, msg.hasMore)
+		if m.loading {
+			return m, tea.Batch(cmd, m.loadMore())
+		}
+		m.list.StopSpinner()
+		return m, cmd
+
+	case tea.KeyMsg:
+		// the keys are typed into the filter while filtering
+		if m.list.FilterState() == list.Filtering {
+			break
+		}
+
+		switch {
+		case key.Matches(msg, m.keymap.quit):
+			return m, tea.Quit
+
+		case key.Matches(msg, m.keymap.selectDb):
+			if i, ok := m.list.SelectedItem().(searchItem); ok {
+				m.choice = &i.SearchResult
+			}
+			return m, tea.Quit
+
+		case key.Matches(msg, m.keymap.toggleIds):
+			showIds = !showIds
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+// the chosen result is printed once the list is closed
+func (m searchListModel) View() string {
+	if m.choice != nil {
+		return ""
+	}
+	return "\n" + m.list.View()
+}
+
+// appends the results, the order of the search is kept
+func (m *searchListModel) addResults(results []notion.SearchResult, hasMore bool) tea.Cmd {
+	m.loading = hasMore
+	items := m.list.Items()
+	for _, result := range results {
+		items = append(items, searchItem{result})
+	}
+	return m.list.SetItems(items)
+}
+
+func newSearchListModel(results []notion.SearchResult, opts SearchListOptions) *searchListModel {
+	keymap := getDbListKeyMap()
+	keymap.selectDb.SetHelp(keymap.selectDb.Help().Key, "print URL")
+
+	l := newListModel(nil, opts.Title)
+	l.SetDelegate(searchDelegate{})
+	l.KeyMap.Quit = keymap.quit
+	l.SetStatusBarItemName("result", "results")
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{keymap.selectDb, keymap.toggleIds}
+	}
+
+	m := searchListModel{list: l, opts: opts, keymap: keymap, loading: opts.LoadMore != nil}
+	m.addResults(results, m.loading)
+	return &m
+}
+
+// shows the results and returns the chosen one, false when the list was quit
+func InitSearchList(results []notion.SearchResult, opts SearchListOptions) (notion.SearchResult, bool) {
+	m := newSearchListModel(results, opts)
+	if plain {
+		// the numbers of the results change when more of them are added, so all are loaded first
+		for m.loading {
+			results, hasMore, err := opts.LoadMore()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return notion.SearchResult{}, false
+			}
+			m.addResults(results, hasMore)
+		}
+		return runPlainSearchList(*m)
+	}
+
+	detectBackground()
+	model, err := tea.NewProgram(*m).Run()
+	if err != nil {
+		fmt.Println("Error running program:", err)
+		os.Exit(1)
+	}
+	if choice := model.(searchListModel).choice; choice != nil {
+		return *choice, true
+	}
+	return notion.SearchResult{}, false
+}
@@ -0,0 +1,254 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/ChmaraX/notidb/internal/settings"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+const (
+	AutoTheme         = "auto"
+	DarkTheme         = "dark"
+	LightTheme        = "light"
+	HighContrastTheme = "high-contrast"
+)
+
+// colors of the TUI, see applyTheme for where they are used
+type Theme struct {
+	Accent   lipgloss.TerminalColor
+	Muted    lipgloss.TerminalColor
+	Error    lipgloss.TerminalColor
+	Success  lipgloss.TerminalColor
+	Warning  lipgloss.TerminalColor
+	Selected lipgloss.TerminalColor
+	// selected items are bold, so they stand out without colors
+	Bold bool
+}
+
+var builtinThemes = map[string]Theme{
+	DarkTheme: {
+		Accent:   lipgloss.Color("#FF06B7"),
+		Muted:    lipgloss.Color("#767676"),
+		Error:    lipgloss.Color("#E05252"),
+		Success:  lipgloss.Color("42"),
+		Warning:  lipgloss.Color("#E5A50A"),
+		Selected: lipgloss.Color("170"),
+	},
+	LightTheme: {
+		Accent:   lipgloss.Color("#C3008C"),
+		Muted:    lipgloss.Color("#6C6C6C"),
+		Error:    lipgloss.Color("#B3261E"),
+		Success:  lipgloss.Color("#1A7F37"),
+		Warning:  lipgloss.Color("#9A6700"),
+		Selected: lipgloss.Color("#7B2FBE"),
+	},
+	// the basic ANSI colors, which terminals keep readable on their own background
+	HighContrastTheme: {
+		Accent:   lipgloss.AdaptiveColor{Light: "5", Dark: "13"},
+		Muted:    lipgloss.AdaptiveColor{Light: "0", Dark: "15"},
+		Error:    lipgloss.AdaptiveColor{Light: "1", Dark: "9"},
+		Success:  lipgloss.AdaptiveColor{Light: "2", Dark: "10"},
+		Warning:  lipgloss.AdaptiveColor{Light: "3", Dark: "11"},
+		Selected: lipgloss.AdaptiveColor{Light: "4", Dark: "14"},
+		Bold:     true,
+	},
+}
+
+var colorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+var (
+	inputStyle          lipgloss.Style
+	errorStyle          lipgloss.Style
+	helpKeyStyle        lipgloss.Style
+	scrollHintStyle     lipgloss.Style
+	selectedFieldStyle  lipgloss.Style
+	hiddenFieldStyle    lipgloss.Style
+	selectedItemStyle   lipgloss.Style
+	highlightStyle      lipgloss.Style
+	spinnerStyle        lipgloss.Style
+	previewMutedStyle   lipgloss.Style
+	previewCodeStyle    lipgloss.Style
+	previewCalloutStyle lipgloss.Style
+	previewQuoteStyle   lipgloss.Style
+	checkMark           = &mark{symbol: "✓"}
+	crossMark           = &mark{symbol: "✗"}
+	warningMark         = &mark{symbol: "!"}
+)
+
+// whether the theme has adaptive colors, which need the terminal background
+var (
+	adaptive   bool
+	detectOnce sync.Once
+)
+
+func init() {
+	// the background is assumed dark until detectBackground queries the terminal
+	lipgloss.SetHasDarkBackground(true)
+	applyTheme(builtinThemes[DarkTheme])
+}
+
+// returns the names of the built-in themes and the user-defined ones
+func ThemeNames(custom map[string]*settings.ThemeConfig) []string {
+	names := []string{AutoTheme}
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	for name := range custom {
+		if _, ok := builtinThemes[name]; !ok && name != AutoTheme {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// returns the theme by name, the user-defined themes take precedence over the built-in ones,
+// "auto" or no name picks the dark or light theme by the terminal background
+func ResolveTheme(name string, custom map[string]*settings.ThemeConfig) (Theme, error) {
+	if name == "" {
+		name = AutoTheme
+	}
+
+	config, ok := custom[name]
+	if !ok {
+		if name == AutoTheme {
+			return autoTheme(), nil
+		}
+		if t, ok := builtinThemes[name]; ok {
+			return t, nil
+		}
+		return Theme{}, fmt.Errorf("unknown theme %q, available: %v", name, ThemeNames(custom))
+	}
+
+	// a user-defined theme can override a built-in one of the same name
+	base := config.Base
+	if base == "" {
+		base = AutoTheme
+		if _, ok := builtinThemes[name]; ok {
+			base = name
+		}
+	}
+	var t Theme
+	if base == AutoTheme {
+		t = autoTheme()
+	} else if t, ok = builtinThemes[base]; !ok {
+		return Theme{}, fmt.Errorf("theme %q: unknown base theme %q", name, base)
+	}
+
+	colors := []struct {
+		name  string
+		value string
+		color *lipgloss.TerminalColor
+	}{
+		{"accent", config.Accent, &t.Accent},
+		{"muted", config.Muted, &t.Muted},
+		{"error", config.Error, &t.Error},
+		{"success", config.Success, &t.Success},
+		{"warning", config.Warning, &t.Warning},
+		{"selected", config.Selected, &t.Selected},
+	}
+	for _, c := range colors {
+		if c.value == "" {
+			continue
+		}
+		if !isValidColor(c.value) {
+			return Theme{}, fmt.Errorf("theme %q: %s color %q must be hex code like #FF06B7 or ANSI number 0-255", name, c.name, c.value)
+		}
+		*c.color = lipgloss.Color(c.value)
+	}
+	return t, nil
+}
+
+// returns the dark or the light theme, picked by the background when rendered
+func autoTheme() Theme {
+	dark, light := builtinThemes[DarkTheme], builtinThemes[LightTheme]
+	adaptiveColor := func(dark, light lipgloss.TerminalColor) lipgloss.TerminalColor {
+		return lipgloss.AdaptiveColor{Dark: string(dark.(lipgloss.Color)), Light: string(light.(lipgloss.Color))}
+	}
+	return Theme{
+		Accent:   adaptiveColor(dark.Accent, light.Accent),
+		Muted:    adaptiveColor(dark.Muted, light.Muted),
+		Error:    adaptiveColor(dark.Error, light.Error),
+		Success:  adaptiveColor(dark.Success, light.Success),
+		Warning:  adaptiveColor(dark.Warning, light.Warning),
+		Selected: adaptiveColor(dark.Selected, light.Selected),
+	}
+}
+
+// queries the terminal background once, before the first TUI is shown, so commands
+// without one don't wait for the terminal, plain mode keeps the dark background
+func detectBackground() {
+	detectOnce.Do(func() {
+		if adaptive && !plain {
+			lipgloss.SetHasDarkBackground(termenv.NewOutput(os.Stdout).HasDarkBackground())
+		}
+	})
+}
+
+func isValidColor(value string) bool {
+	if colorPattern.MatchString(value) {
+		return true
+	}
+	n, err := strconv.Atoi(value)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// sets the theme of all TUI components, colors are left out when NO_COLOR is set
+func SetTheme(t Theme) {
+	adaptive = false
+	if os.Getenv("NO_COLOR") != "" {
+		lipgloss.SetColorProfile(termenv.Ascii)
+		t.Bold = true
+	} else {
+		for _, color := range []lipgloss.TerminalColor{t.Accent, t.Muted, t.Error, t.Success, t.Warning, t.Selected} {
+			if _, ok := color.(lipgloss.AdaptiveColor); ok {
+				adaptive = true
+			}
+		}
+	}
+	applyTheme(t)
+}
+
+// mark printed with messages, rendered when printed so it follows the theme
+// and the detected background
+type mark struct {
+	symbol string
+	style  lipgloss.Style
+}
+
+func (m *mark) String() string {
+	return m.style.Render(m.symbol)
+}
+
+// returns the marks printed with success, failure and warning messages
+func Marks() (check, cross, warning fmt.Stringer) {
+	return checkMark, crossMark, warningMark
+}
+
+func applyTheme(t Theme) {
+	inputStyle = lipgloss.NewStyle().Foreground(t.Accent).MarginLeft(2)
+	errorStyle = lipgloss.NewStyle().Foreground(t.Error).MarginLeft(2)
+	helpKeyStyle = lipgloss.NewStyle().Foreground(t.Muted)
+	scrollHintStyle = lipgloss.NewStyle().Foreground(t.Muted).MarginLeft(2)
+	selectedFieldStyle = lipgloss.NewStyle().Foreground(t.Accent).Bold(t.Bold)
+	hiddenFieldStyle = lipgloss.NewStyle().Foreground(t.Muted)
+	selectedItemStyle = lipgloss.NewStyle().PaddingLeft(2).Foreground(t.Selected).Bold(t.Bold)
+	highlightStyle = lipgloss.NewStyle().Foreground(t.Selected).Bold(t.Bold)
+	spinnerStyle = lipgloss.NewStyle().Foreground(t.Accent)
+
+	previewMutedStyle = lipgloss.NewStyle().Foreground(t.Muted)
+	previewCodeStyle = lipgloss.NewStyle().Border(lipgloss.NormalBorder(), false, false, false, true).BorderForeground(t.Muted).PaddingLeft(1)
+	previewCalloutStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(t.Muted).PaddingRight(1)
+	previewQuoteStyle = lipgloss.NewStyle().Border(lipgloss.ThickBorder(), false, false, false, true).BorderForeground(t.Accent).PaddingLeft(1)
+
+	checkMark.style = lipgloss.NewStyle().Foreground(t.Success)
+	crossMark.style = lipgloss.NewStyle().Foreground(t.Error)
+	warningMark.style = lipgloss.NewStyle().Foreground(t.Warning)
+}
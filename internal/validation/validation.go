@@ -0,0 +1,74 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ChmaraX/notidb/internal/notion"
+	"github.com/ChmaraX/notidb/internal/settings"
+	"github.com/jomei/notionapi"
+)
+
+// checks the text value of a property against its type and the rules of its form field,
+// the field can be nil when the database has no rules for the property
+func Validate(propType notionapi.PropertyType, value string, field *settings.FieldConfig) error {
+	if strings.TrimSpace(value) == "" {
+		if field != nil && field.Required {
+			return fmt.Errorf("is required")
+		}
+		return nil
+	}
+
+	if err := notion.ValidateFormat(propType, value); err != nil {
+		return err
+	}
+	if field == nil {
+		return nil
+	}
+
+	if field.Pattern != "" {
+		pattern, err := regexp.Compile(`^(?:` + field.Pattern + `)$`)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q in the config: %v", field.Pattern, err)
+		}
+		if !pattern.MatchString(value) {
+			return fmt.Errorf("must match %s", field.Pattern)
+		}
+	}
+
+	if propType == notionapi.PropertyTypeNumber {
+		// the format was already checked
+		number, _ := strconv.ParseFloat(value, 64)
+		if field.Min != nil && number < *field.Min {
+			return fmt.Errorf("must be at least %v", *field.Min)
+		}
+		if field.Max != nil && number > *field.Max {
+			return fmt.Errorf("must be at most %v", *field.Max)
+		}
+	}
+
+	if len(field.Options) > 0 {
+		options := []string{value}
+		if propType == notionapi.PropertyTypeMultiSelect {
+			options = notion.SplitOptions(value)
+		}
+		for _, option := range options {
+			if !contains(field.Options, option) {
+				return fmt.Errorf("%q is not one of: %s", option, strings.Join(field.Options, ", "))
+			}
+		}
+	}
+
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/ChmaraX/notidb/internal/settings"
+	"github.com/jomei/notionapi"
+)
+
+func TestValidate(t *testing.T) {
+	min, max := 1.0, 10.0
+
+	tests := []struct {
+		name     string
+		propType notionapi.PropertyType
+		value    string
+		field    *settings.FieldConfig
+		valid    bool
+	}{
+		{"empty without rules", notionapi.PropertyTypeRichText, "", nil, true},
+		{"empty required", notionapi.PropertyTypeRichText, " ", &settings.FieldConfig{Required: true}, false},
+		{"filled required", notionapi.PropertyTypeRichText, "text", &settings.FieldConfig{Required: true}, true},
+		{"invalid format", notionapi.PropertyTypeEmail, "not an email", nil, false},
+		{"pattern match", notionapi.PropertyTypeRichText, "ABC-123", &settings.FieldConfig{Pattern: `[A-Z]+-\d+`}, true},
+		{"pattern matches whole value", notionapi.PropertyTypeRichText, "x ABC-123", &settings.FieldConfig{Pattern: `[A-Z]+-\d+`}, false},
+		{"invalid pattern", notionapi.PropertyTypeRichText, "text", &settings.FieldConfig{Pattern: `(`}, false},
+		{"number in range", notionapi.PropertyTypeNumber, "5", &settings.FieldConfig{Min: &min, Max: &max}, true},
+		{"number below min", notionapi.PropertyTypeNumber, "0", &settings.FieldConfig{Min: &min}, false},
+		{"number above max", notionapi.PropertyTypeNumber, "11", &settings.FieldConfig{Max: &max}, false},
+		{"allowed option", notionapi.PropertyTypeSelect, "Todo", &settings.FieldConfig{Options: []string{"Todo", "Done"}}, true},
+		{"unknown option", notionapi.PropertyTypeSelect, "Later", &settings.FieldConfig{Options: []string{"Todo", "Done"}}, false},
+		{"allowed multi-select options", notionapi.PropertyTypeMultiSelect, "a, b", &settings.FieldConfig{Options: []string{"a", "b"}}, true},
+		{"unknown multi-select option", notionapi.PropertyTypeMultiSelect, "a, c", &settings.FieldConfig{Options: []string{"a", "b"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.propType, tt.value, tt.field)
+			if (err == nil) != tt.valid {
+				t.Errorf("Validate(%s, %q) error = %v, want valid %v", tt.propType, tt.value, err, tt.valid)
+			}
+		})
+	}
+}